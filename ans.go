@@ -0,0 +1,183 @@
+package ascii
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ansWidth is the line width of a DOS text-mode screen.
+const ansWidth = 80
+
+// ANSICell is one character of a BBS .ANS screen. Foreground is an ANSI
+// color index from 0 to 15, drawn bold for 8-15; Background is 0-7.
+type ANSICell struct {
+	Char       rune
+	Foreground uint8
+	Background uint8
+}
+
+// cp437 maps the non-ASCII characters this package renders to their DOS
+// code page 437 bytes.
+var cp437 = map[rune]byte{
+	'░': 0xb0, '▒': 0xb1, '▓': 0xb2, '█': 0xdb,
+	'▄': 0xdc, '▌': 0xdd, '▐': 0xde, '▀': 0xdf,
+	'■': 0xfe, '·': 0xfa,
+	'│': 0xb3, '─': 0xc4, '┌': 0xda, '┐': 0xbf, '└': 0xc0, '┘': 0xd9,
+	'║': 0xba, '═': 0xcd, '╔': 0xc9, '╗': 0xbb, '╚': 0xc8, '╝': 0xbc,
+}
+
+// toCP437 returns the code page 437 byte for r, or '?' when it has none.
+func toCP437(r rune) byte {
+	if r >= ' ' && r < 0x7f {
+		return byte(r)
+	}
+	if b, ok := cp437[r]; ok {
+		return b
+	}
+	return '?'
+}
+
+// WriteANS writes grid as CP437 text with ANSI color escapes. Rows wider
+// than 80 columns wrap onto the next line, and a line only ends in CRLF
+// when it is shorter than 80 columns, as a DOS screen wraps full lines by
+// itself.
+func WriteANS(w io.Writer, grid [][]ANSICell) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("\x1b[0m")
+	fg, bg := -1, -1
+	for _, row := range grid {
+		for start := 0; start < len(row) || start == 0; start += ansWidth {
+			line := row[start:min(start+ansWidth, len(row))]
+			for _, cell := range line {
+				if int(cell.Foreground) != fg || int(cell.Background&7) != bg {
+					fg, bg = int(cell.Foreground), int(cell.Background&7)
+					bold := "0"
+					if fg >= 8 {
+						bold = "1"
+					}
+					fmt.Fprintf(bw, "\x1b[%s;%d;%dm", bold, 30+fg%8, 40+bg)
+				}
+				bw.WriteByte(toCP437(cell.Char))
+			}
+			if len(line) < ansWidth {
+				bw.WriteString("\r\n")
+			}
+		}
+	}
+	bw.WriteString("\x1b[0m")
+	return bw.Flush()
+}
+
+// SAUCERecord is the metadata footer of a BBS art file.
+type SAUCERecord struct {
+	Title  string
+	Author string
+	Group  string
+	Date   time.Time
+	// FileSize is the length of the art before the record.
+	FileSize uint32
+	// Width and Height are the size of the screen in characters.
+	Width, Height uint16
+}
+
+// WriteSAUCE writes an end-of-file marker followed by a 128-byte SAUCE 00
+// record describing an ANSi character file.
+func WriteSAUCE(w io.Writer, meta SAUCERecord) error {
+	record := make([]byte, 0, 129)
+	record = append(record, 0x1a)
+	record = append(record, "SAUCE00"...)
+	record = appendSAUCEString(record, meta.Title, 35, ' ')
+	record = appendSAUCEString(record, meta.Author, 20, ' ')
+	record = appendSAUCEString(record, meta.Group, 20, ' ')
+	date := meta.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	record = append(record, date.Format("20060102")...)
+	record = append(record, byte(meta.FileSize), byte(meta.FileSize>>8), byte(meta.FileSize>>16), byte(meta.FileSize>>24))
+	// DataType 1 (Character), FileType 1 (ANSi).
+	record = append(record, 1, 1)
+	record = append(record, byte(meta.Width), byte(meta.Width>>8), byte(meta.Height), byte(meta.Height>>8))
+	// TInfo3, TInfo4, Comments and TFlags.
+	record = append(record, 0, 0, 0, 0, 0, 0)
+	record = appendSAUCEString(record, "IBM VGA", 22, 0)
+	_, err := w.Write(record)
+	return err
+}
+
+// appendSAUCEString appends s as a fixed-width CP437 field padded with
+// pad.
+func appendSAUCEString(dst []byte, s string, width int, pad byte) []byte {
+	n := 0
+	for _, r := range s {
+		if n == width {
+			break
+		}
+		dst = append(dst, toCP437(r))
+		n++
+	}
+	for ; n < width; n++ {
+		dst = append(dst, pad)
+	}
+	return dst
+}
+
+// ANSEncoder writes a BBS .ANS file: 16-color ANSI art in CP437 wrapped at
+// 80 columns, followed by a SAUCE record. Rows are buffered until End so
+// the record can give the file size. Without Color every character is
+// light gray on black.
+type ANSEncoder struct {
+	Color                bool
+	Title, Author, Group string
+
+	grid [][]ANSICell
+}
+
+func (e *ANSEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.grid = make([][]ANSICell, 0, rows)
+	return nil
+}
+
+func (e *ANSEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	cells := make([]ANSICell, len(row))
+	for x, cell := range row {
+		cells[x] = ANSICell{Char: cell.Char, Foreground: 7}
+		if !e.Color {
+			continue
+		}
+		c := cell.Color
+		cells[x].Foreground = uint8(ansi16(int(c.R), int(c.G), int(c.B)))
+		if cell.HasBackground {
+			bg := cell.Background
+			cells[x].Background = uint8(ansi8(int(bg.R), int(bg.G), int(bg.B)))
+		}
+	}
+	e.grid = append(e.grid, cells)
+	return nil
+}
+
+func (e *ANSEncoder) End(w io.Writer) error {
+	var art strings.Builder
+	if err := WriteANS(&art, e.grid); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, art.String()); err != nil {
+		return err
+	}
+	width, height := 0, 0
+	for _, row := range e.grid {
+		width = max(width, min(len(row), ansWidth))
+		height += max((len(row)+ansWidth-1)/ansWidth, 1)
+	}
+	return WriteSAUCE(w, SAUCERecord{
+		Title:    e.Title,
+		Author:   e.Author,
+		Group:    e.Group,
+		FileSize: uint32(art.Len()),
+		Width:    uint16(width),
+		Height:   uint16(height),
+	})
+}
@@ -0,0 +1,33 @@
+package ascii
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteSAUCE(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("art")
+	meta := SAUCERecord{Title: "t", Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), FileSize: 3, Width: 80, Height: 25}
+	if err := WriteSAUCE(&buf, meta); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+	if got, want := len(out), len("art")+1+128; got != want {
+		t.Fatalf("len = %d, want %d", got, want)
+	}
+	record := out[len(out)-128:]
+	if out[len(out)-129] != 0x1a {
+		t.Errorf("byte before record = %#x, want EOF marker 0x1a", out[len(out)-129])
+	}
+	if !bytes.HasPrefix(record, []byte("SAUCE00")) {
+		t.Errorf("record starts %q, want SAUCE00", record[:7])
+	}
+	if got := string(record[82:90]); got != "20240102" {
+		t.Errorf("date = %q, want 20240102", got)
+	}
+	if got := string(record[106:113]); got != "IBM VGA" {
+		t.Errorf("TInfoS = %q, want IBM VGA", got)
+	}
+}
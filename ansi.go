@@ -0,0 +1,192 @@
+package ascii
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// ColorMode selects how many colors ANSI output may use.
+type ColorMode int
+
+const (
+	ColorNone ColorMode = iota
+	// ColorTrueColor emits 24-bit escapes (\x1b[38;2;R;G;Bm).
+	ColorTrueColor
+	// Color256 maps to the xterm 256-color palette (\x1b[38;5;Nm).
+	Color256
+	// Color16 maps to the 16 standard ANSI foreground colors.
+	Color16
+	// Color8 maps to the 8 basic ANSI colors (\x1b[30m-\x1b[37m).
+	Color8
+)
+
+// ParseColorMode converts a -color value to a ColorMode. "true" and "false"
+// are accepted for compatibility with the former boolean flag.
+func ParseColorMode(name string) (ColorMode, error) {
+	switch name {
+	case "none", "false", "":
+		return ColorNone, nil
+	case "truecolor", "true":
+		return ColorTrueColor, nil
+	case "256":
+		return Color256, nil
+	case "16":
+		return Color16, nil
+	case "8":
+		return Color8, nil
+	}
+	return 0, fmt.Errorf("unknown color mode %q", name)
+}
+
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+var ansi16Palette = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// ansi8Palette holds black, red, green, yellow, blue, magenta, cyan and white
+// in ANSI order.
+var ansi8Palette = [8][3]int{
+	{0, 0, 0}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+func colorDistance(r1, g1, b1, r2, g2, b2 int) int {
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return dr*dr + dg*dg + db*db
+}
+
+func nearestCubeLevel(v int) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if abs(v-level) < abs(v-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// ansi256 returns the xterm-256 palette index closest to an RGB color,
+// choosing between the 6×6×6 color cube and the 24-step grayscale ramp.
+func ansi256(r, g, b int) int {
+	ri, gi, bi := nearestCubeLevel(r), nearestCubeLevel(g), nearestCubeLevel(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := colorDistance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	grayIndex := min(max(((r+g+b)/3-8+5)/10, 0), 23)
+	gray := 8 + 10*grayIndex
+	if colorDistance(r, g, b, gray, gray, gray) < cubeDist {
+		return 232 + grayIndex
+	}
+	return cube
+}
+
+// ansi16 returns the index (0-15) of the standard ANSI color closest to an
+// RGB color.
+func ansi16(r, g, b int) int {
+	best, bestDist := 0, -1
+	for i, p := range ansi16Palette {
+		if d := colorDistance(r, g, b, p[0], p[1], p[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// ansi8 returns the index (0-7) of the basic ANSI color closest to an RGB
+// color.
+func ansi8(r, g, b int) int {
+	best, bestDist := 0, -1
+	for i, p := range ansi8Palette {
+		if d := colorDistance(r, g, b, p[0], p[1], p[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// ansiForeground returns the escape sequence that sets the foreground color
+// for mode, or "" for ColorNone.
+func ansiForeground(mode ColorMode, r, g, b int) string {
+	return ansiColor(mode, r, g, b, false)
+}
+
+// ansiBackground is like ansiForeground but sets the background color.
+func ansiBackground(mode ColorMode, r, g, b int) string {
+	return ansiColor(mode, r, g, b, true)
+}
+
+func ansiColor(mode ColorMode, r, g, b int, background bool) string {
+	extended, basic, bright := 38, 30, 90
+	if background {
+		extended, basic, bright = 48, 40, 100
+	}
+	switch mode {
+	case ColorTrueColor:
+		return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", extended, r, g, b)
+	case Color256:
+		return fmt.Sprintf("\x1b[%d;5;%dm", extended, ansi256(r, g, b))
+	case Color16:
+		n := ansi16(r, g, b)
+		if n >= 8 {
+			return fmt.Sprintf("\x1b[%dm", bright+n-8)
+		}
+		return fmt.Sprintf("\x1b[%dm", basic+n)
+	case Color8:
+		return fmt.Sprintf("\x1b[%dm", basic+ansi8(r, g, b))
+	}
+	return ""
+}
+
+// Background describes the ANSI background painted behind each character.
+type Background struct {
+	// Auto paints black behind bright characters and white behind dark
+	// ones instead of using Color.
+	Auto  bool
+	Color color.RGBA
+}
+
+// ParseBackground parses a -bg-color value: black, white, auto or #RRGGBB.
+// It returns nil for "" and "none".
+func ParseBackground(spec string) (*Background, error) {
+	switch spec {
+	case "", "none":
+		return nil, nil
+	case "black":
+		return &Background{Color: color.RGBA{0, 0, 0, 255}}, nil
+	case "white":
+		return &Background{Color: color.RGBA{255, 255, 255, 255}}, nil
+	case "auto":
+		return &Background{Auto: true}, nil
+	}
+	var r, g, b uint8
+	if len(spec) != 7 || spec[0] != '#' {
+		return nil, fmt.Errorf("invalid background color %q", spec)
+	}
+	if _, err := fmt.Sscanf(spec[1:], "%02x%02x%02x", &r, &g, &b); err != nil {
+		return nil, fmt.Errorf("invalid background color %q", spec)
+	}
+	return &Background{Color: color.RGBA{r, g, b, 255}}, nil
+}
+
+// colorFor returns the background to paint behind a character drawn in fg.
+func (bg *Background) colorFor(fg color.RGBA) color.RGBA {
+	if !bg.Auto {
+		return bg.Color
+	}
+	luma := 0.2126*float64(fg.R) + 0.7152*float64(fg.G) + 0.0722*float64(fg.B)
+	if luma > 127.5 {
+		return color.RGBA{0, 0, 0, 255}
+	}
+	return color.RGBA{255, 255, 255, 255}
+}
@@ -0,0 +1,39 @@
+package ascii
+
+import "testing"
+
+func TestANSI8(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		r, g, b int
+		want    int
+	}{
+		{"black", 0, 0, 0, 0},
+		{"red", 255, 0, 0, 1},
+		{"green", 0, 255, 0, 2},
+		{"yellow", 255, 255, 0, 3},
+		{"blue", 0, 0, 255, 4},
+		{"magenta", 255, 0, 255, 5},
+		{"cyan", 0, 255, 255, 6},
+		{"white", 255, 255, 255, 7},
+		{"dark red", 150, 20, 10, 1},
+		{"dark grey", 40, 40, 40, 0},
+		{"light grey", 220, 220, 220, 7},
+		{"orange", 255, 165, 0, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ansi8(tc.r, tc.g, tc.b); got != tc.want {
+				t.Errorf("ansi8(%d, %d, %d) = %d, want %d", tc.r, tc.g, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestANSIColor8(t *testing.T) {
+	if got, want := ansiForeground(Color8, 0, 0, 255), "\x1b[34m"; got != want {
+		t.Errorf("foreground = %q, want %q", got, want)
+	}
+	if got, want := ansiBackground(Color8, 0, 255, 0), "\x1b[42m"; got != want {
+		t.Errorf("background = %q, want %q", got, want)
+	}
+}
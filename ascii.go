@@ -0,0 +1,463 @@
+// Package ascii converts images into character art for terminals.
+package ascii
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCharAspect is the width-to-height ratio of a typical terminal character cell.
+const DefaultCharAspect = 0.5
+
+// Options controls how pixels are converted to characters.
+type Options struct {
+	// Width is the number of columns to render.
+	Width int
+	// Height is the number of rows to render. When zero it is derived from
+	// Width, the source aspect ratio and CharAspect.
+	Height int
+	// Letterbox fits the image inside Width×Height without distortion and
+	// pads the remaining cells with spaces.
+	Letterbox bool
+	// Fill crops the image to the aspect ratio of Width×Height so that it
+	// fills the grid without distortion. Letterbox takes precedence.
+	Fill bool
+	// CharAspect is the width-to-height ratio of a character cell;
+	// DefaultCharAspect is used when zero.
+	CharAspect float64
+	// Interpolation selects the resampling algorithm.
+	Interpolation Interpolation
+	// AutoLevels stretches the brightness range of the resized image to
+	// cover the whole palette.
+	AutoLevels bool
+	// Equalize remaps brightness through the cumulative histogram of the
+	// resized image so every palette character is used about equally.
+	Equalize bool
+	// Luma weights the color channels when computing brightness; the zero
+	// value means LumaWeights709.
+	Luma LumaWeights
+	// Gamma linearizes gamma-encoded pixel values before mapping; zero or
+	// 1 disables it.
+	Gamma float64
+	// Brightness is added to every pixel's brightness, in the range [-1, 1].
+	Brightness float64
+	// Contrast, when set, scales brightness around the midpoint: 0 flattens
+	// every pixel to mid-grey and values above 1 increase contrast.
+	Contrast *float64
+	// Threshold, when positive, renders pixels darker than it with the first
+	// palette character and all others with the last.
+	Threshold float64
+	// Dither selects how brightness is quantized to palette levels.
+	Dither Dither
+	// Edges replaces pixels whose Sobel gradient magnitude exceeds
+	// EdgeThreshold with a line character following the edge.
+	Edges bool
+	// EdgesOnly draws only the edge characters, leaving every other cell
+	// blank, for a line-art rendering in ModeASCII.
+	EdgesOnly     bool
+	EdgeThreshold float64
+	// EdgeWeight, when set, scales the gradient magnitude that Edges
+	// compares with EdgeThreshold, blending more or fewer outlines into
+	// the brightness rendering: 0 draws none. EdgesOnly ignores it.
+	EdgeWeight *float64
+	// Color selects ANSI foreground colors for the default TextEncoder. In
+	// ModeQuarterBlock it also enables two-color cells.
+	Color ColorMode
+	// Invert reverses the brightness mapping for light terminal backgrounds.
+	Invert bool
+	// Chars is the palette to draw with; asciiChars is used when empty.
+	Chars CharSet
+	// Blur is the standard deviation, in source pixels, of a Gaussian blur
+	// applied before resizing. Zero disables it.
+	Blur float64
+	// Sharpen is the amount of unsharp masking applied before resizing.
+	// Zero disables it.
+	Sharpen float64
+	// ColorBlindness simulates a color vision deficiency on the source
+	// image.
+	ColorBlindness ColorBlindness
+	// Sepia tones the source image before brightness and colors are taken
+	// from it.
+	Sepia bool
+	// Negative replaces every source color with its complement. Unlike
+	// Invert, it changes the colors as well as the characters.
+	Negative bool
+	// Saturation, when set, scales the HSL saturation of the source image:
+	// 0 is grayscale and values above 1 are more vivid than the original.
+	Saturation *float64
+	// HueShift rotates the hue of the source image by this many degrees.
+	HueShift float64
+	// Posterize reduces every color channel of the source image to this
+	// many levels; values below 2 disable it. It runs before brightness is
+	// computed, so Dither always diffuses the error of the posterized image
+	// over the palette rather than posterizing a dithered one.
+	Posterize int
+	// Workers is the number of goroutines used to fill rows; values below 2
+	// render sequentially.
+	Workers int
+	// Stream writes each row as soon as it is computed instead of buffering
+	// the whole image, rendering sequentially regardless of Workers.
+	Stream bool
+	// RowDelay pauses between streamed rows.
+	RowDelay time.Duration
+	// Mode selects how pixels are packed into character cells.
+	Mode RenderMode
+	// BrailleThreshold is the brightness at which a Braille dot is drawn;
+	// 0.5 is used when zero.
+	BrailleThreshold float64
+	// Encoder selects the output format; a TextEncoder honoring Color is
+	// used when nil.
+	Encoder Encoder
+	// MaxInputPixels, when positive, rejects source images with more pixels
+	// than this before any filtering or resizing.
+	MaxInputPixels int
+	// ProgressFunc, when set, is called after each stage of a render with
+	// how long it took. The stages are "preprocess" (filters), "resize"
+	// (sampling and levels), "render" (filling and encoding rows) and
+	// "output" (finishing and flushing the encoder).
+	ProgressFunc func(stage string, elapsed time.Duration)
+}
+
+// Converter renders images using a fixed set of Options.
+type Converter struct {
+	Options Options
+}
+
+// NewConverter returns a Converter for opts.
+func NewConverter(opts Options) *Converter {
+	return &Converter{Options: opts}
+}
+
+// ScaledHeight returns the number of rows that keeps a bounds-sized image
+// visually proportional when rendered width columns wide.
+func ScaledHeight(bounds image.Rectangle, width int, charAspect float64) int {
+	if bounds.Dx() == 0 {
+		return 1
+	}
+	height := int(float64(width) * float64(bounds.Dy()) / float64(bounds.Dx()) * charAspect)
+	return max(height, 1)
+}
+
+// Render returns img rendered as text.
+func (c *Converter) Render(img image.Image) (string, error) {
+	var sb strings.Builder
+	if err := c.RenderToWriter(img, &sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// RenderToWriter writes img rendered as text to w.
+func (c *Converter) RenderToWriter(img image.Image, w io.Writer) error {
+	return c.RenderContext(context.Background(), img, w)
+}
+
+// RenderContext is like RenderToWriter but stops before the next stage or
+// row and returns ctx.Err() once ctx is cancelled.
+func (c *Converter) RenderContext(ctx context.Context, img image.Image, w io.Writer) error {
+	opts := c.Options
+	rows, width, height, err := c.rowRenderer(ctx, img)
+	if err != nil {
+		return err
+	}
+	enc := c.encoder()
+
+	var grid [][]Cell
+	start := time.Now()
+	if opts.Workers > 1 && !opts.Stream {
+		if grid, err = renderParallel(ctx, rows, width, height, opts.Workers); err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := enc.Begin(bw, width, height); err != nil {
+		return err
+	}
+	row := make([]Cell, width)
+	for y := 0; y < height; y++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if grid != nil {
+			row = grid[y]
+		} else {
+			rows.row(y, row)
+		}
+		if err := enc.WriteRow(bw, y, row); err != nil {
+			return err
+		}
+		if opts.Stream {
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			if err := sleepContext(ctx, opts.RowDelay); err != nil {
+				return err
+			}
+		}
+	}
+	opts.progress("render", start)
+
+	start = time.Now()
+	if err := enc.End(bw); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	opts.progress("output", start)
+	return nil
+}
+
+// progress reports a finished stage to ProgressFunc.
+func (opts Options) progress(stage string, start time.Time) {
+	if opts.ProgressFunc != nil {
+		opts.ProgressFunc(stage, time.Since(start))
+	}
+}
+
+// sleepContext waits for d, returning early with the context's error if ctx
+// is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rowRenderer resolves the output size and prepares img for rendering. It
+// returns ctx.Err() instead of starting the filter or resize stage once ctx
+// is cancelled.
+func (c *Converter) rowRenderer(ctx context.Context, img image.Image) (rows rowRenderer, width, height int, err error) {
+	opts := c.Options
+	width, height = opts.Width, opts.Height
+	aspect := opts.CharAspect
+	if aspect == 0 {
+		aspect = DefaultCharAspect
+	}
+	if err := checkInputPixels(img.Bounds(), opts.MaxInputPixels); err != nil {
+		return nil, 0, 0, err
+	}
+	if height == 0 {
+		height = ScaledHeight(img.Bounds(), width, aspect)
+	}
+	if width <= 0 || height <= 0 {
+		return nil, 0, 0, fmt.Errorf("%w: %dx%d", ErrInvalidDimensions, width, height)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+	start := time.Now()
+	img = preprocess(img, opts)
+	opts.progress("preprocess", start)
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+	start = time.Now()
+	switch {
+	case opts.Letterbox:
+		rows = newLetterboxRows(img, width, height, aspect, opts)
+	case opts.Fill:
+		rows = newRowRenderer(fillCrop(img, width, height, aspect), width, height, opts)
+	default:
+		rows = newRowRenderer(img, width, height, opts)
+	}
+	opts.progress("resize", start)
+	return rows, width, height, nil
+}
+
+// checkInputPixels reports an image whose bounds hold more than limit
+// pixels. A limit of zero or less disables the check.
+func checkInputPixels(bounds image.Rectangle, limit int) error {
+	if limit > 0 && int64(bounds.Dx())*int64(bounds.Dy()) > int64(limit) {
+		return fmt.Errorf("%w: %dx%d image has more than %d pixels", ErrInvalidDimensions, bounds.Dx(), bounds.Dy(), limit)
+	}
+	return nil
+}
+
+// encoder returns the configured Encoder or the default TextEncoder.
+func (c *Converter) encoder() Encoder {
+	if c.Options.Encoder != nil {
+		return c.Options.Encoder
+	}
+	return &TextEncoder{Color: c.Options.Color}
+}
+
+// renderParallel fills every row using up to workers goroutines. Once ctx
+// is cancelled it starts no more rows and, after those running finish,
+// returns ctx.Err().
+func renderParallel(ctx context.Context, rows rowRenderer, width, height, workers int) ([][]Cell, error) {
+	grid := make([][]Cell, height)
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for y := range grid {
+		if ctx.Err() != nil {
+			break
+		}
+		grid[y] = make([]Cell, width)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(y int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			rows.row(y, grid[y])
+		}(y)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return grid, nil
+}
+
+// lumaWeights returns opts.Luma, or LumaWeights709 when it is unset.
+func (opts Options) lumaWeights() LumaWeights {
+	if opts.Luma == (LumaWeights{}) {
+		return LumaWeights709
+	}
+	return opts.Luma
+}
+
+func brightnessGrid(img image.Image, opts Options) [][]float64 {
+	bounds := img.Bounds()
+	luma := opts.lumaWeights()
+	grid := make([][]float64, bounds.Dy())
+	for y := range grid {
+		grid[y] = make([]float64, bounds.Dx())
+		for x := range grid[y] {
+			grid[y][x] = pixelBrightness(img.At(bounds.Min.X+x, bounds.Min.Y+y), luma, opts.Gamma)
+		}
+	}
+	if opts.AutoLevels {
+		lo, hi := computeBrightnessRange(grid)
+		grid = stretchBrightness(grid, lo, hi)
+	}
+	if opts.Equalize && len(grid) > 0 {
+		w := len(grid[0])
+		flat := make([]float64, 0, len(grid)*w)
+		for _, row := range grid {
+			flat = append(flat, row...)
+		}
+		flat = equalizeHistogram(flat)
+		for y := range grid {
+			copy(grid[y], flat[y*w:])
+		}
+	}
+	for y := range grid {
+		for x := range grid[y] {
+			grid[y][x] = adjustTone(grid[y][x], opts)
+		}
+	}
+	return grid
+}
+
+// computeBrightnessRange returns the smallest and largest values in a
+// brightness grid.
+func computeBrightnessRange(brightness [][]float64) (lo, hi float64) {
+	lo, hi = 1, 0
+	for _, row := range brightness {
+		for _, v := range row {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	return lo, hi
+}
+
+// stretchBrightness linearly maps [lo, hi] onto [0, 1] in place. A flat grid
+// is returned unchanged.
+func stretchBrightness(brightness [][]float64, lo, hi float64) [][]float64 {
+	if hi <= lo {
+		return brightness
+	}
+	for _, row := range brightness {
+		for x, v := range row {
+			row[x] = (v - lo) / (hi - lo)
+		}
+	}
+	return brightness
+}
+
+// equalizeHistogram remaps row-major brightness values in [0, 1] through
+// the cumulative distribution of their 256-bucket histogram.
+func equalizeHistogram(brightness []float64) []float64 {
+	bucket := func(v float64) int {
+		return int(math.Round(math.Min(math.Max(v, 0), 1) * 255))
+	}
+	var cdf [256]int
+	for _, v := range brightness {
+		cdf[bucket(v)]++
+	}
+	for i := 1; i < len(cdf); i++ {
+		cdf[i] += cdf[i-1]
+	}
+	cdfMin := 0
+	for _, c := range cdf {
+		if c > 0 {
+			cdfMin = c
+			break
+		}
+	}
+	out := make([]float64, len(brightness))
+	total := len(brightness)
+	if total == cdfMin {
+		copy(out, brightness)
+		return out
+	}
+	for i, v := range brightness {
+		out[i] = float64(cdf[bucket(v)]-cdfMin) / float64(total-cdfMin)
+	}
+	return out
+}
+
+// PixelToASCII maps a single pixel to a character using its luminance.
+func PixelToASCII(c color.Color, opts Options) rune {
+	scale := adjustTone(pixelBrightness(c, opts.lumaWeights(), opts.Gamma), opts)
+	chars := opts.Chars
+	if len(chars) == 0 {
+		chars = asciiChars
+	}
+	return chars.Pick(scale, opts.Invert)
+}
+
+// pixelBrightness returns the luminance of c in [0, 1], raised to gamma to
+// linearize sRGB-encoded values. A gamma of 0 or 1 leaves it unchanged.
+func pixelBrightness(c color.Color, luma LumaWeights, gamma float64) float64 {
+	r, g, b, _ := c.RGBA()
+	red := float64(r) / 257.0
+	green := float64(g) / 257.0
+	blue := float64(b) / 257.0
+	brightness := luma.R*red + luma.G*green + luma.B*blue
+	scale := brightness / 255.0
+	if gamma != 0 && gamma != 1 {
+		scale = math.Pow(scale, gamma)
+	}
+	return scale
+}
+
+// adjustTone applies the contrast and brightness options to a brightness in
+// [0, 1] and clamps the result to the same range.
+func adjustTone(v float64, opts Options) float64 {
+	if opts.Contrast != nil {
+		v = (v-0.5)**opts.Contrast + 0.5
+	}
+	v += opts.Brightness
+	return math.Min(math.Max(v, 0), 1)
+}
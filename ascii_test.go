@@ -0,0 +1,296 @@
+package ascii
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// grayRamp returns a w×1 image whose gray level rises evenly from lo to hi.
+func grayRamp(w int, lo, hi uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, 1))
+	for x := range img.Pix {
+		img.Pix[x] = lo + uint8((int(hi-lo)*x+(w-1)/2)/(w-1))
+	}
+	return img
+}
+
+func TestAdjustTone(t *testing.T) {
+	ptr := func(v float64) *float64 { return &v }
+	for _, tc := range []struct {
+		name string
+		opts Options
+		in   float64
+		want float64
+	}{
+		{"unset is identity", Options{}, 0.3, 0.3},
+		{"zero brightness and unit contrast are identity", Options{Brightness: 0, Contrast: ptr(1)}, 0.3, 0.3},
+		{"brightness adds", Options{Brightness: 0.25}, 0.5, 0.75},
+		{"contrast scales around the midpoint", Options{Contrast: ptr(2)}, 0.4, 0.3},
+		{"zero contrast flattens to mid-grey", Options{Contrast: ptr(0)}, 0.9, 0.5},
+		{"clamped above", Options{Brightness: 1}, 0.5, 1},
+		{"clamped below", Options{Contrast: ptr(3)}, 0.1, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := adjustTone(tc.in, tc.opts); got < tc.want-1e-9 || got > tc.want+1e-9 {
+				t.Errorf("adjustTone(%v) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPixelToASCII(t *testing.T) {
+	last := asciiChars[len(asciiChars)-1]
+	for _, tc := range []struct {
+		name string
+		c    color.Color
+		want rune
+	}{
+		{"black", color.Black, asciiChars[0]},
+		{"white", color.White, last},
+		{"50% gray", color.Gray{Y: 128}, asciiChars[len(asciiChars)/2]},
+		{"transparent", color.NRGBA{R: 255, G: 255, B: 255, A: 0}, asciiChars[0]},
+	} {
+		if got := PixelToASCII(tc.c, Options{}); got != tc.want {
+			t.Errorf("%s: PixelToASCII = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// Every gray level picks a palette character, the same one each time,
+	// however far the tone options push it.
+	for _, opts := range []Options{{}, {Brightness: 1}, {Brightness: -1}, {Gamma: 2.2}, {Chars: CharSet("ab")}} {
+		chars := opts.Chars
+		if len(chars) == 0 {
+			chars = asciiChars
+		}
+		for y := 0; y < 256; y++ {
+			c := color.Gray{Y: uint8(y)}
+			got := PixelToASCII(c, opts)
+			if !strings.ContainsRune(string(chars), got) {
+				t.Errorf("PixelToASCII(%v, %+v) = %q, not in the palette", c, opts, got)
+			}
+			if again := PixelToASCII(c, opts); again != got {
+				t.Errorf("PixelToASCII(%v, %+v) = %q then %q", c, opts, got, again)
+			}
+		}
+	}
+}
+
+func TestComputeBrightnessRange(t *testing.T) {
+	lo, hi := computeBrightnessRange([][]float64{{0.4, 0.3}, {0.9, 0.5}})
+	if lo != 0.3 || hi != 0.9 {
+		t.Errorf("computeBrightnessRange = %v, %v, want 0.3, 0.9", lo, hi)
+	}
+}
+
+func TestStretchBrightness(t *testing.T) {
+	got := stretchBrightness([][]float64{{0.3, 0.5, 0.7}}, 0.3, 0.7)
+	for i, want := range []float64{0, 0.5, 1} {
+		if d := got[0][i] - want; d < -1e-9 || d > 1e-9 {
+			t.Errorf("stretched value %d = %v, want %v", i, got[0][i], want)
+		}
+	}
+}
+
+func TestAutoLevelsUsesFullPalette(t *testing.T) {
+	// The darkest pixel is at 30% brightness.
+	img := grayRamp(len(asciiChars), 77, 255)
+	for _, autoLevels := range []bool{false, true} {
+		out, err := NewConverter(Options{Width: len(asciiChars), Height: 1, AutoLevels: autoLevels}).Render(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		usesAll := true
+		for _, c := range asciiChars {
+			usesAll = usesAll && strings.ContainsRune(out, c)
+		}
+		if usesAll != autoLevels {
+			t.Errorf("AutoLevels %v: got %q, full palette used: %v", autoLevels, out, usesAll)
+		}
+	}
+}
+
+// noise returns a w×h image of pseudo-random colors.
+func noise(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	seed := uint32(1)
+	for i := range img.Pix {
+		seed = seed*1664525 + 1013904223
+		img.Pix[i] = uint8(seed >> 24)
+	}
+	return img
+}
+
+func TestRenderParallelMatchesSequential(t *testing.T) {
+	img := noise(200, 100)
+	want, err := NewConverter(Options{Width: 100, Height: 50}).Render(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewConverter(Options{Width: 100, Height: 50, Workers: 4}).Render(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Error("parallel render differs from the sequential one")
+	}
+}
+
+func benchmarkRender(b *testing.B, workers int) {
+	img := noise(1600, 800)
+	c := NewConverter(Options{Width: 400, Height: 200, Workers: workers})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.RenderToWriter(img, io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderSequential(b *testing.B) { benchmarkRender(b, 1) }
+
+func BenchmarkRenderParallel(b *testing.B) { benchmarkRender(b, runtime.GOMAXPROCS(0)) }
+
+// cancelAfter cancels a render once it has encoded n rows.
+type cancelAfter struct {
+	Encoder
+	n      int
+	rows   int
+	cancel context.CancelFunc
+}
+
+func (e *cancelAfter) WriteRow(w io.Writer, y int, row []Cell) error {
+	e.rows++
+	if e.rows == e.n {
+		e.cancel()
+	}
+	return e.Encoder.WriteRow(w, y, row)
+}
+
+func TestRenderContextCancel(t *testing.T) {
+	const height = 20
+	for _, workers := range []int{1, 4} {
+		ctx, cancel := context.WithCancel(context.Background())
+		enc := &cancelAfter{Encoder: &TextEncoder{}, n: 5, cancel: cancel}
+		err := NewConverter(Options{Width: 10, Height: height, Workers: workers, Encoder: enc}).RenderContext(ctx, noise(20, 20), io.Discard)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("workers %d: RenderContext error = %v, want context.Canceled", workers, err)
+		}
+		if enc.rows >= height {
+			t.Errorf("workers %d: %d rows written after cancelling, want fewer than %d", workers, enc.rows, height)
+		}
+
+		// A render cancelled before it starts neither resizes nor fills
+		// any rows.
+		enc = &cancelAfter{Encoder: &TextEncoder{}, cancel: cancel}
+		var stages []string
+		opts := Options{Width: 10, Height: height, Workers: workers, Encoder: enc, ProgressFunc: func(stage string, _ time.Duration) {
+			stages = append(stages, stage)
+		}}
+		if err := NewConverter(opts).RenderContext(ctx, noise(20, 20), io.Discard); !errors.Is(err, context.Canceled) {
+			t.Errorf("workers %d: cancelled RenderContext error = %v, want context.Canceled", workers, err)
+		}
+		if enc.rows != 0 || len(stages) != 0 {
+			t.Errorf("workers %d: cancelled render wrote %d rows and ran stages %q, want none", workers, enc.rows, stages)
+		}
+	}
+}
+
+// cancellingRows is a rowRenderer that cancels its context once it has
+// filled n rows.
+type cancellingRows struct {
+	n      int32
+	filled atomic.Int32
+	cancel context.CancelFunc
+}
+
+func (r *cancellingRows) row(y int, dst []Cell) {
+	if r.filled.Add(1) == r.n {
+		r.cancel()
+	}
+}
+
+func TestRenderParallelCancel(t *testing.T) {
+	const height = 1000
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rows := &cancellingRows{n: 10, cancel: cancel}
+	grid, err := renderParallel(ctx, rows, 4, height, 4)
+	if !errors.Is(err, context.Canceled) || grid != nil {
+		t.Errorf("renderParallel = %d rows, %v, want context.Canceled", len(grid), err)
+	}
+	if n := rows.filled.Load(); n >= height {
+		t.Errorf("%d rows filled after cancelling, want fewer than %d", n, height)
+	}
+}
+
+func TestEqualizeHistogramFlattens(t *testing.T) {
+	// Evenly spread values squeezed into [0.4, 0.6] should spread over
+	// [0, 1].
+	const n = 1000
+	in := make([]float64, n)
+	for i := range in {
+		in[i] = 0.4 + 0.2*float64(i)/(n-1)
+	}
+	out := equalizeHistogram(in)
+	for i, v := range out {
+		if want := float64(i) / (n - 1); math.Abs(v-want) > 0.05 {
+			t.Fatalf("value %d = %.3f, want about %.3f", i, v, want)
+		}
+	}
+	if out[0] != 0 || out[n-1] != 1 {
+		t.Errorf("range = [%v, %v], want [0, 1]", out[0], out[n-1])
+	}
+}
+
+func TestEqualizeHistogramConstant(t *testing.T) {
+	in := []float64{0.5, 0.5, 0.5}
+	if got := equalizeHistogram(in); got[0] != 0.5 || got[1] != 0.5 || got[2] != 0.5 {
+		t.Errorf("equalizeHistogram(%v) = %v, want it unchanged", in, got)
+	}
+}
+
+// hugeImage reports large bounds and fails the test if a pixel is read.
+type hugeImage struct {
+	t      *testing.T
+	bounds image.Rectangle
+}
+
+func (h hugeImage) ColorModel() color.Model { return color.RGBAModel }
+func (h hugeImage) Bounds() image.Rectangle { return h.bounds }
+func (h hugeImage) At(x, y int) color.Color {
+	h.t.Fatalf("At(%d, %d) called on an image over the pixel limit", x, y)
+	return nil
+}
+
+func TestMaxInputPixels(t *testing.T) {
+	const limit = 100_000_000
+	img := hugeImage{t, image.Rect(0, 0, 20_000, 10_000)} // 200 MP
+	_, err := NewConverter(Options{Width: 80, MaxInputPixels: limit}).Render(img)
+	if !errors.Is(err, ErrInvalidDimensions) {
+		t.Errorf("Render of a 200 MP image = %v, want ErrInvalidDimensions", err)
+	}
+
+	for _, tc := range []struct {
+		bounds  image.Rectangle
+		limit   int
+		wantErr bool
+	}{
+		{image.Rect(0, 0, 10_000, 10_000), limit, false},
+		{image.Rect(0, 0, 10_000, 10_001), limit, true},
+		{image.Rect(5, 5, 10_005, 10_005), limit, false},
+		{image.Rect(0, 0, 1<<20, 1<<20), 0, false},
+	} {
+		if err := checkInputPixels(tc.bounds, tc.limit); (err != nil) != tc.wantErr {
+			t.Errorf("checkInputPixels(%v, %d) = %v, want error: %v", tc.bounds, tc.limit, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ascii
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// BorderStyle selects the characters used to frame the output.
+type BorderStyle int
+
+const (
+	BorderNone BorderStyle = iota
+	// BorderASCII draws the frame with + - and |.
+	BorderASCII
+	// BorderSingle draws the frame with single-line box-drawing characters.
+	BorderSingle
+	// BorderDouble draws the frame with double-line box-drawing characters.
+	BorderDouble
+)
+
+// borderChars holds the top-left, top-right, bottom-left and bottom-right
+// corners followed by the horizontal and vertical rules of each style.
+var borderChars = map[BorderStyle][6]rune{
+	BorderASCII:  {'+', '+', '+', '+', '-', '|'},
+	BorderSingle: {'┌', '┐', '└', '┘', '─', '│'},
+	BorderDouble: {'╔', '╗', '╚', '╝', '═', '║'},
+}
+
+// ParseBorderStyle converts a name such as "single" to a BorderStyle.
+func ParseBorderStyle(name string) (BorderStyle, error) {
+	switch name {
+	case "none", "":
+		return BorderNone, nil
+	case "ascii":
+		return BorderASCII, nil
+	case "single":
+		return BorderSingle, nil
+	case "double":
+		return BorderDouble, nil
+	}
+	return 0, fmt.Errorf("unknown border %q", name)
+}
+
+// BorderEncoder frames the grid written by Encoder with a border one cell
+// wide, so the inner encoder sees two more columns and rows than rendered.
+type BorderEncoder struct {
+	Encoder Encoder
+	Style   BorderStyle
+
+	cols, rows int
+	row        []Cell
+}
+
+func (e *BorderEncoder) Begin(w io.Writer, cols, rows int) error {
+	if e.Style == BorderNone {
+		return e.Encoder.Begin(w, cols, rows)
+	}
+	e.cols, e.rows = cols, rows
+	e.row = make([]Cell, cols+2)
+	if err := e.Encoder.Begin(w, cols+2, rows+2); err != nil {
+		return err
+	}
+	chars := borderChars[e.Style]
+	return e.Encoder.WriteRow(w, 0, e.rule(chars[0], chars[4], chars[1]))
+}
+
+func (e *BorderEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	if e.Style == BorderNone {
+		return e.Encoder.WriteRow(w, y, row)
+	}
+	edge := borderChars[e.Style][5]
+	e.row[0] = borderCell(edge)
+	copy(e.row[1:], row)
+	e.row[len(e.row)-1] = borderCell(edge)
+	return e.Encoder.WriteRow(w, y+1, e.row)
+}
+
+func (e *BorderEncoder) End(w io.Writer) error {
+	if e.Style == BorderNone {
+		return e.Encoder.End(w)
+	}
+	chars := borderChars[e.Style]
+	if err := e.Encoder.WriteRow(w, e.rows+1, e.rule(chars[2], chars[4], chars[3])); err != nil {
+		return err
+	}
+	return e.Encoder.End(w)
+}
+
+// rule returns a horizontal border row.
+func (e *BorderEncoder) rule(left, fill, right rune) []Cell {
+	e.row[0] = borderCell(left)
+	for x := 1; x <= e.cols; x++ {
+		e.row[x] = borderCell(fill)
+	}
+	e.row[len(e.row)-1] = borderCell(right)
+	return e.row
+}
+
+func borderCell(r rune) Cell {
+	return Cell{Char: r, Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Brightness: 1}
+}
@@ -0,0 +1,26 @@
+package ascii
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteCaptionLine writes caption centered in width columns, followed by a
+// line of hyphens as wide as the output. Captions longer than width are
+// truncated with "...".
+func WriteCaptionLine(w io.Writer, caption string, width int) error {
+	runes := []rune(caption)
+	if len(runes) > width {
+		if width > 3 {
+			runes = append(runes[:width-3], []rune("...")...)
+		} else {
+			runes = runes[:max(width, 0)]
+		}
+	}
+	text := string(runes)
+	pad := (width - utf8.RuneCountInString(text)) / 2
+	_, err := fmt.Fprintf(w, "%s%s\n%s\n", strings.Repeat(" ", pad), text, strings.Repeat("-", max(width, 0)))
+	return err
+}
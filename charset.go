@@ -0,0 +1,92 @@
+package ascii
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CharSet is a character palette ordered from least to most visually dense.
+// The first rune is used for the darkest pixels and the last for the brightest.
+type CharSet []rune
+
+var asciiChars = CharSet(" ·:-=+*#%@█")
+
+var charsets = map[string]CharSet{
+	"standard": asciiChars,
+	"blocks":   CharSet(" ░▒▓█"),
+	"braille":  CharSet("⠀⠁⠃⠇⠏⠟⠿⡿⣿"),
+	"dense":    CharSet(" .'`^\",:;Il!i><~+_-?][}{1)(|\\/tfjrxnuvczXYUJCLQ0OZmwqpdbkhao*#MW&8%B@$"),
+}
+
+var charsetDescriptions = map[string]string{
+	"standard": "symbol ramp from a middle dot to a full block",
+	"blocks":   "shade blocks, U+2591–U+2593 and U+2588",
+	"braille":  "2×4 Braille dot patterns, U+2800–U+28FF",
+	"dense":    "70 printable ASCII characters, for fine gradients",
+}
+
+// CharSetNames returns the names of the built-in palettes in sorted order.
+func CharSetNames() []string {
+	names := make([]string, 0, len(charsets))
+	for name := range charsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CharSetDescription returns a one-line description of a built-in palette.
+func CharSetDescription(name string) string {
+	return charsetDescriptions[name]
+}
+
+// NamedCharSet returns one of the built-in palettes: standard, blocks,
+// braille or dense.
+func NamedCharSet(name string) (CharSet, error) {
+	cs, ok := charsets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown charset %q", name)
+	}
+	return cs, nil
+}
+
+// ParseCharSet builds a CharSet from a string of characters. The string must
+// already be ordered from least to most visually dense.
+func ParseCharSet(chars string) (CharSet, error) {
+	if chars == "" {
+		return nil, fmt.Errorf("character set is empty")
+	}
+	return CharSet(chars), nil
+}
+
+// ASCIIOnly returns the printable 7-bit ASCII characters of cs, in order.
+func (cs CharSet) ASCIIOnly() CharSet {
+	var out CharSet
+	for _, r := range cs {
+		if r >= ' ' && r < 0x7f {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Index returns the palette position nearest a brightness in the range
+// [0, 1].
+func (cs CharSet) Index(scale float64) int {
+	return clampIndex(int(math.Round(scale*float64(len(cs)-1))), len(cs))
+}
+
+// Pick returns the character for a brightness in the range [0, 1].
+func (cs CharSet) Pick(scale float64, invert bool) rune {
+	return cs.At(cs.Index(scale), invert)
+}
+
+// At returns the character at a palette position, counting from the dense
+// end when invert is set.
+func (cs CharSet) At(index int, invert bool) rune {
+	if invert {
+		index = len(cs) - 1 - index
+	}
+	return cs[index]
+}
@@ -0,0 +1,30 @@
+package ascii
+
+import "testing"
+
+func TestCharSetIndex(t *testing.T) {
+	for _, name := range []string{"standard", "blocks", "braille"} {
+		cs, err := NamedCharSet(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := len(cs) - 1
+		for _, tc := range []struct {
+			scale float64
+			want  int
+		}{
+			{0, 0},
+			{0.5, last / 2},
+			{1, last},
+			{-0.1, 0},
+			{1.1, last},
+		} {
+			if got := cs.Index(tc.scale); got != tc.want {
+				t.Errorf("%s: Index(%v) = %d, want %d", name, tc.scale, got, tc.want)
+			}
+		}
+		if got, want := cs.Pick(0.5, false), cs[last/2]; got != want {
+			t.Errorf("%s: Pick(0.5) = %q, want the middle character %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"image/gif"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// parseFrameRange expands a selector such as "0-10,15" into frame indices.
+// An empty selector selects every frame.
+func parseFrameRange(spec string, count int) ([]int, error) {
+	if spec == "" {
+		frames := make([]int, count)
+		for i := range frames {
+			frames[i] = i
+		}
+		return frames, nil
+	}
+
+	var frames []int
+	for _, part := range strings.Split(spec, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		start, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frame %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("invalid frame %q", part)
+			}
+		}
+		if start < 0 || end < start || end >= count {
+			return nil, fmt.Errorf("frame range %q outside 0-%d", part, count-1)
+		}
+		for i := start; i <= end; i++ {
+			frames = append(frames, i)
+		}
+	}
+	return frames, nil
+}
+
+// playAnimation renders the selected GIF frames, transformed by geometry,
+// and replays them in place. A positive fps overrides the per-frame delays stored in the GIF; loops is
+// the number of passes, or -1 to repeat forever.
+func playAnimation(w io.Writer, g *gif.GIF, geometry transform, converter *ascii.Converter, selected []int, fps float64, loops int) error {
+	frames := ascii.CoalesceGIF(g)
+	rendered := make([]string, len(selected))
+	delays := make([]time.Duration, len(selected))
+	for i, index := range selected {
+		frame, err := geometry.apply(frames[index])
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", index, err)
+		}
+		text, err := converter.Render(frame)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", index, err)
+		}
+		rendered[i] = text
+		delays[i] = 100 * time.Millisecond
+		if fps > 0 {
+			delays[i] = time.Duration(float64(time.Second) / fps)
+		} else if index < len(g.Delay) && g.Delay[index] > 0 {
+			delays[i] = time.Duration(g.Delay[index]) * 10 * time.Millisecond
+		}
+	}
+	if len(rendered) == 0 {
+		return nil
+	}
+
+	if _, err := io.WriteString(w, "\x1b[2J"); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(delays[0])
+	defer ticker.Stop()
+	for pass := 0; loops < 0 || pass < loops; pass++ {
+		for i, text := range rendered {
+			if _, err := io.WriteString(w, "\x1b[H"+text); err != nil {
+				return err
+			}
+			ticker.Reset(delays[i])
+			<-ticker.C
+		}
+	}
+	return nil
+}
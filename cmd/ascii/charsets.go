@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// listCharsets prints each built-in palette with its characters and a
+// description.
+func listCharsets(w io.Writer) error {
+	var sb strings.Builder
+	for _, name := range ascii.CharSetNames() {
+		cs, _ := ascii.NamedCharSet(name)
+		fmt.Fprintf(&sb, "%-9s %s\n          %q\n", name+":", ascii.CharSetDescription(name), string(cs))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// previewCharset draws a dark-to-bright gradient bar width characters wide
+// in cs.
+func previewCharset(w io.Writer, cs ascii.CharSet, width int) error {
+	bar := make([]rune, width)
+	for x := range bar {
+		bar[x] = cs.Pick(float64(x)/float64(max(width-1, 1)), false)
+	}
+	line := string(bar) + "\n"
+	_, err := io.WriteString(w, line+line+line)
+	return err
+}
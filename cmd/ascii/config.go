@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configPath returns the config file to read: the -config flag, then
+// $ASCII_CONFIG, then $HOME/.ascii.yaml. explicit reports whether the user
+// named the file, in which case it must exist.
+func configPath(flagValue string) (path string, explicit bool) {
+	if flagValue != "" {
+		return flagValue, true
+	}
+	if env := os.Getenv("ASCII_CONFIG"); env != "" {
+		return env, true
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, ".ascii.yaml"), false
+}
+
+// loadConfig reads a config file of "flag-name: value" lines, a flat subset
+// of YAML. Blank lines and # comments are ignored, and values may be
+// quoted.
+func loadConfig(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || text == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key: value", path, line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			if value, err = unquoteValue(value); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+			}
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// unquoteValue strips the quotes from a config value, dropping anything after
+// the closing quote. Double-quoted values take Go escapes such as \" and \\;
+// in single-quoted ones, as in YAML, a doubled quote stands for one.
+func unquoteValue(value string) (string, error) {
+	if value[0] == '"' {
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", fmt.Errorf("unterminated or invalid string %s", value)
+		}
+		return strconv.Unquote(quoted)
+	}
+	var sb strings.Builder
+	for i := 1; i < len(value); i++ {
+		if value[i] != '\'' {
+			sb.WriteByte(value[i])
+		} else if i+1 < len(value) && value[i+1] == '\'' {
+			sb.WriteByte('\'')
+			i++
+		} else {
+			return sb.String(), nil
+		}
+	}
+	return "", fmt.Errorf("unterminated string %s", value)
+}
+
+// applyConfig sets each flag named in the config at path unless it was given
+// on the command line, and records it in set. A missing file is only an
+// error when explicit is set.
+func applyConfig(path string, explicit bool, set map[string]bool) error {
+	values, err := loadConfig(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		if name == "config" || flag.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown option %q", path, name)
+		}
+		if set[name] {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("%s: invalid %s: %v", path, name, err)
+		}
+		set[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content to a config file in a temporary directory and
+// returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ascii.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want string
+	}{
+		{`chars: abc`, "abc"},
+		{`chars: abc # comment`, "abc"},
+		{`chars: "a#b"`, "a#b"},
+		{`chars: "say \"hi\"" # comment`, `say "hi"`},
+		{`chars: "back\\slash"`, `back\slash`},
+		{`chars: 'it''s'`, "it's"},
+		{`chars: ''`, ""},
+	} {
+		got, err := loadConfig(writeConfig(t, tc.line+"\n"))
+		if err != nil {
+			t.Errorf("%s: %v", tc.line, err)
+			continue
+		}
+		if want := map[string]string{"chars": tc.want}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: loadConfig = %q, want %q", tc.line, got, want)
+		}
+	}
+
+	for _, line := range []string{`chars: "abc`, `chars: "ab\"`, `chars: 'it''s`} {
+		if _, err := loadConfig(writeConfig(t, line+"\n")); err == nil {
+			t.Errorf("%s: loadConfig succeeded, want an unterminated string error", line)
+		}
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	saved := flag.CommandLine
+	defer func() { flag.CommandLine = saved }()
+
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		config string
+		want   int
+	}{
+		{"default", nil, "", 100},
+		{"config beats default", nil, "width: 60\n", 60},
+		{"flag beats config", []string{"-width", "40"}, "width: 60\n", 40},
+	} {
+		flag.CommandLine = flag.NewFlagSet("ascii", flag.ContinueOnError)
+		width := flag.Int("width", 100, "")
+		if err := flag.CommandLine.Parse(tc.args); err != nil {
+			t.Fatal(err)
+		}
+		set := map[string]bool{}
+		flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		if err := applyConfig(writeConfig(t, tc.config), true, set); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if *width != tc.want {
+			t.Errorf("%s: -width = %d, want %d", tc.name, *width, tc.want)
+		}
+	}
+}
+
+func TestApplyConfigFlagTypes(t *testing.T) {
+	saved := flag.CommandLine
+	defer func() { flag.CommandLine = saved }()
+	flag.CommandLine = flag.NewFlagSet("ascii", flag.ContinueOnError)
+	width := flag.Int("width", 100, "")
+	maxBytes := flag.Int64("max-stdin-bytes", 1, "")
+	gamma := flag.Float64("gamma", 2.2, "")
+	invert := flag.Bool("invert", false, "")
+	timeout := flag.Duration("timeout", time.Second, "")
+	chars := flag.String("chars", "", "")
+
+	set := map[string]bool{}
+	config := "width: 60\nmax-stdin-bytes: 4096\ngamma: 1.8\ninvert: true\ntimeout: 1m30s\nchars: 'a b'\n"
+	if err := applyConfig(writeConfig(t, config), true, set); err != nil {
+		t.Fatal(err)
+	}
+	if *width != 60 || *maxBytes != 4096 || *gamma != 1.8 || !*invert || *timeout != 90*time.Second || *chars != "a b" {
+		t.Errorf("applied width %d, max-stdin-bytes %d, gamma %v, invert %v, timeout %v, chars %q",
+			*width, *maxBytes, *gamma, *invert, *timeout, *chars)
+	}
+	want := map[string]bool{"width": true, "max-stdin-bytes": true, "gamma": true, "invert": true, "timeout": true, "chars": true}
+	if !reflect.DeepEqual(set, want) {
+		t.Errorf("set = %v, want %v", set, want)
+	}
+
+	for _, config := range []string{
+		"width: wide\n",
+		"max-stdin-bytes: -\n",
+		"gamma: high\n",
+		"invert: maybe\n",
+		"timeout: 30\n",
+		"colour: true\n",
+		"config: other.yaml\n",
+	} {
+		if err := applyConfig(writeConfig(t, config), true, map[string]bool{}); err == nil {
+			t.Errorf("%q: applyConfig succeeded, want an error", config)
+		}
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	input := writePNG(t, 8, 8)
+	for _, tc := range []struct {
+		config string
+		want   string
+	}{
+		{"width: 4\nheight: 2\nchars: \"ab\"\n", "bbbb\nbbbb\n"},
+		{"width: 3\nheight: 1\nchars: \"ab\"\nbrightness: -1.0\n", "aaa\n"},
+		{"width: 3\nheight: 1\nchars: \"ab\"\ninvert: true\nframe-delay: 0s\n", "aaa\n"},
+	} {
+		config := writeConfig(t, tc.config)
+		stdout, stderr, err := runMain(t, "-config", config, "-quiet", input)
+		if err != nil {
+			t.Errorf("%q: %v\n%s", tc.config, err, stderr)
+			continue
+		}
+		if stdout != tc.want {
+			t.Errorf("%q: rendered %q, want %q", tc.config, stdout, tc.want)
+		}
+	}
+
+	for _, tc := range []struct{ config, wantErr string }{
+		{"colour: true\n", `unknown option "colour"`},
+		{"frame-delay: soon\n", "invalid frame-delay"},
+	} {
+		config := writeConfig(t, tc.config)
+		_, stderr, err := runMain(t, "-config", config, input)
+		if err == nil || !strings.Contains(stderr, tc.wantErr) {
+			t.Errorf("%q: err %v, stderr %q, want an error containing %q", tc.config, err, stderr, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// writeDiff prints the cell-by-cell difference between two renderings.
+// Changed cells show the new character in red, or '!' without color;
+// unchanged cells show a dimmed '.'.
+func writeDiff(w io.Writer, a, b [][]rune, color bool) error {
+	bw := bufio.NewWriter(w)
+	for y, row := range ascii.DiffRenders(a, b) {
+		for x, r := range row {
+			changed := y >= len(a) || x >= len(a[y]) || y >= len(b) || x >= len(b[y]) || a[y][x] != b[y][x]
+			switch {
+			case changed && color:
+				bw.WriteString("\x1b[31m")
+				bw.WriteRune(r)
+				bw.WriteString("\x1b[0m")
+			case changed:
+				bw.WriteByte('!')
+			case color:
+				bw.WriteString("\x1b[2m.\x1b[0m")
+			default:
+				bw.WriteByte('.')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
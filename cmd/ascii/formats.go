@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// inputFormats lists the image decoders registered by main's imports.
+var inputFormats = []string{"JPEG", "PNG", "GIF", "WebP", "BMP", "TIFF"}
+
+// outputFormats lists the -format values newEncoder accepts.
+var outputFormats = []struct{ name, description string }{
+	{"text", "plain text, ANSI colored with -color"},
+	{"html", "HTML <pre> fragment"},
+	{"html-doc", "standalone HTML document"},
+	{"svg", "SVG image of the characters"},
+	{"json", "JSON array of rows of cells"},
+	{"rtf", "RTF document in a monospaced font"},
+	{"ans", "BBS .ANS art in CP437 with a SAUCE record"},
+	{"png", "PNG image of the characters"},
+	{"sixel", "SIXEL inline graphics"},
+	{"kitty", "kitty terminal graphics protocol"},
+	{"iterm2", "iTerm2 inline image"},
+	{"markdown", "fenced Markdown code block"},
+	{"markdown-ansi", "```ansi Markdown code block with ANSI colors"},
+}
+
+// textFormat reports whether a -format writes plain lines of text, which a
+// caption or a metadata block can be added to without corrupting the output.
+func textFormat(name string) bool {
+	switch name {
+	case "text", "markdown", "markdown-ansi":
+		return true
+	}
+	return false
+}
+
+// listFormats prints the supported input and output formats and which
+// inline graphics protocol the current terminal is detected to support.
+func listFormats(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Input formats:\n  %s\n\nOutput formats (-format):\n", strings.Join(inputFormats, ", "))
+	for _, f := range outputFormats {
+		fmt.Fprintf(&sb, "  %-14s %s\n", f.name, f.description)
+	}
+	detected := detectGraphicsFormat()
+	sb.WriteString("\nTerminal graphics:\n")
+	for _, name := range []string{"kitty", "iterm2"} {
+		status := "not detected"
+		if name == detected {
+			status = "detected, used when -format is not set"
+		}
+		fmt.Fprintf(&sb, "  %-14s %s\n", name, status)
+	}
+	fmt.Fprintf(&sb, "  %-14s %s\n", "sixel", "not auto-detected, select with -format sixel")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type inputOptions struct {
+	maxBytes      int64
+	timeout       time.Duration
+	userAgent     string
+	tlsSkipVerify bool
+}
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+func isURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// openInput opens the named image. Stdin ("-"), HTTP(S) URLs and ZIP archive
+// entries are buffered in memory so that they can be seeked like a file.
+func openInput(name string, opts inputOptions) (io.ReadSeekCloser, error) {
+	switch {
+	case name == "-":
+		return readAllLimited(os.Stdin, "stdin", opts.maxBytes)
+	case isURL(name):
+		return download(name, opts)
+	case strings.HasPrefix(name, "zip://"):
+		return openZipEntry(name, opts.maxBytes)
+	}
+	return os.Open(name)
+}
+
+func download(url string, opts inputOptions) (io.ReadSeekCloser, error) {
+	client := &http.Client{Timeout: opts.timeout}
+	if opts.tlsSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		client.Transport = transport
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts.userAgent != "" {
+		req.Header.Set("User-Agent", opts.userAgent)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	return readAllLimited(resp.Body, url, opts.maxBytes)
+}
+
+func readAllLimited(r io.Reader, name string, maxBytes int64) (io.ReadSeekCloser, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if n > maxBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes, raise -max-stdin-bytes to allow it", name, maxBytes)
+	}
+	return nopReadSeekCloser{bytes.NewReader(buf.Bytes())}, nil
+}
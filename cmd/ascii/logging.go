@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// jsonLogs is set by -log-format json.
+var jsonLogs bool
+
+// logf logs at level "info" or "warn". In JSON mode the level is passed to
+// jsonLogWriter ahead of a NUL byte; messages logged directly through the
+// log package, such as fatal errors, are level "error".
+func logf(level, format string, args ...any) {
+	switch {
+	case jsonLogs:
+		format = level + "\x00" + format
+	case level == "warn":
+		format = "Warning: " + format
+	}
+	log.Printf(format, args...)
+}
+
+// jsonLogWriter turns each log line into a JSON object with the level and
+// the message. For warnings and errors, the text after the first ": " is
+// reported as the error.
+type jsonLogWriter struct {
+	w io.Writer
+}
+
+func (jw jsonLogWriter) Write(p []byte) (int, error) {
+	entry := struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+		Error string `json:"error,omitempty"`
+		TS    string `json:"ts"`
+	}{Level: "error", TS: time.Now().UTC().Format(time.RFC3339)}
+	line := strings.TrimSuffix(string(p), "\n")
+	if level, rest, ok := strings.Cut(line, "\x00"); ok {
+		entry.Level, line = level, rest
+	}
+	entry.Msg = line
+	if entry.Level != "info" {
+		entry.Msg, entry.Error, _ = strings.Cut(line, ": ")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := jw.w.Write(append(data, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
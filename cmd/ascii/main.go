@@ -0,0 +1,1185 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AbilityJLR/ascii"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+	"golang.org/x/term"
+)
+
+// exitUnsupportedFormat is the exit status when the input is not a
+// decodable image; other failures exit with 1.
+const exitUnsupportedFormat = 3
+
+var verbose, quiet bool
+
+// verbosef logs diagnostic output when -verbose is set.
+func verbosef(format string, args ...any) {
+	if verbose {
+		logf("info", format, args...)
+	}
+}
+
+// warnf logs a recoverable problem unless -quiet is set.
+func warnf(format string, args ...any) {
+	if !quiet {
+		logf("warn", format, args...)
+	}
+}
+
+// TerminalSize reports the column and row count of the terminal attached to stdout.
+func TerminalSize() (width, height int, err error) {
+	return term.GetSize(int(os.Stdout.Fd()))
+}
+
+// detectGraphicsFormat returns the inline image format supported by the
+// terminal named in the environment, or "" for plain terminals.
+func detectGraphicsFormat() string {
+	if os.Getenv("TERM") == "xterm-kitty" || os.Getenv("TERM_PROGRAM") == "kitty" {
+		return "kitty"
+	}
+	if os.Getenv("TERM_PROGRAM") == "iTerm.app" {
+		return "iterm2"
+	}
+	return ""
+}
+
+func newEncoder(format string, color ascii.ColorMode, background *ascii.Background, colorDelta int) (ascii.Encoder, error) {
+	switch format {
+	case "text":
+		return &ascii.TextEncoder{Color: color, Background: background, ColorDelta: colorDelta}, nil
+	case "html":
+		return &ascii.HTMLEncoder{}, nil
+	case "html-doc":
+		return &ascii.HTMLEncoder{Document: true}, nil
+	case "svg":
+		return &ascii.SVGEncoder{Color: color != ascii.ColorNone}, nil
+	case "json":
+		return &ascii.JSONEncoder{Color: color != ascii.ColorNone}, nil
+	case "png":
+		return &ascii.PNGEncoder{Color: color != ascii.ColorNone}, nil
+	case "sixel":
+		return &ascii.SIXELEncoder{Color: color != ascii.ColorNone}, nil
+	case "kitty":
+		return &ascii.KittyEncoder{}, nil
+	case "iterm2":
+		return &ascii.ITerm2Encoder{}, nil
+	case "rtf":
+		return &ascii.RTFEncoder{Color: color != ascii.ColorNone}, nil
+	case "ans":
+		return &ascii.ANSEncoder{Color: color != ascii.ColorNone}, nil
+	case "markdown":
+		return &ascii.MarkdownEncoder{}, nil
+	case "markdown-ansi":
+		if color == ascii.ColorNone {
+			color = ascii.Color16
+		}
+		return &ascii.MarkdownANSIEncoder{TextEncoder: ascii.TextEncoder{Color: color, Background: background, ColorDelta: colorDelta}}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// proportionalDimensions returns the character grid size that renders a
+// srcW×srcH image at scale times its pixel size, correcting the height for
+// the character aspect. Both results are at least 1.
+func proportionalDimensions(srcW, srcH int, scale, charAspect float64) (int, int) {
+	w := int(math.Round(float64(srcW) * scale))
+	h := int(math.Round(float64(srcH) * scale * charAspect))
+	return max(w, 1), max(h, 1)
+}
+
+// parseCrop parses a -crop value of the form X,Y,W,H.
+func parseCrop(spec string) (image.Rectangle, error) {
+	var x, y, w, h int
+	if _, err := fmt.Sscanf(spec, "%d,%d,%d,%d", &x, &y, &w, &h); err != nil {
+		return image.Rectangle{}, fmt.Errorf("want X,Y,W,H, got %q", spec)
+	}
+	if w <= 0 || h <= 0 {
+		return image.Rectangle{}, fmt.Errorf("crop rectangle %q is empty", spec)
+	}
+	return image.Rect(x, y, x+w, y+h), nil
+}
+
+func main() {
+	color := flag.String("color", "none", "ANSI color mode: none, truecolor, 256, 16 or 8")
+	bgColor := flag.String("bg-color", "", "ANSI background color: black, white, auto or #RRGGBB")
+	colorDelta := flag.Int("color-delta", 0, "largest per-channel color change drawn without a new escape")
+	width := flag.Int("width", 80, "output width in characters")
+	height := flag.Int("height", 40, "output height in characters")
+	maxWidth := flag.Int("max-width", 0, "upper bound on the output width, scaling both dimensions down to fit; 0 disables")
+	maxHeight := flag.Int("max-height", 0, "upper bound on the output height, scaling both dimensions down to fit; 0 disables")
+	fit := flag.Bool("fit", false, "keep the aspect ratio inside -width x -height, padding with spaces (default when only one dimension is set)")
+	fill := flag.Bool("fill", false, "crop to keep the aspect ratio while filling -width x -height")
+	scale := flag.Float64("scale", 1, "render at this fraction of the source size instead of -width and -height")
+	scaleX := flag.Float64("scale-x", 0, "horizontal scale, overriding -scale")
+	scaleY := flag.Float64("scale-y", 0, "vertical scale, overriding -scale")
+	fontAspect := flag.Float64("font-aspect", ascii.DefaultCharAspect, "character cell width divided by its height")
+	invert := flag.Bool("invert", false, "reverse the brightness mapping for light backgrounds")
+	asciiOnly := flag.Bool("ascii-only", false, "restrict output to printable 7-bit ASCII")
+	chars := flag.String("chars", "", "custom character palette, ordered from least to most dense")
+	charset := flag.String("charset", "standard", "named character palette: standard, blocks, braille or dense")
+	interp := flag.String("interp", "auto", "resampling algorithm: auto, nearest, bilinear or box")
+	autoLevels := flag.Bool("auto-levels", false, "stretch the brightness range to use the full palette")
+	equalize := flag.Bool("equalize", false, "equalize the brightness histogram to use the whole palette")
+	luma := flag.String("luma", "709", "luminance coefficients: 709, 601 or 2020")
+	gamma := flag.Float64("gamma", 2.2, "gamma used to linearize pixel values, 1.0 disables")
+	blur := flag.Float64("blur", 0, "Gaussian blur standard deviation in source pixels, applied before resizing; 0 disables")
+	sharpen := flag.Float64("sharpen", 0, "unsharp mask amount from 0 to 5, applied before resizing; 0 disables")
+	colorBlind := flag.String("color-blind", "none", "simulate color blindness: none, deuteranopia, protanopia or tritanopia")
+	sepia := flag.Bool("sepia", false, "apply a sepia tone before rendering")
+	negative := flag.Bool("negative", false, "replace every color with its complement before rendering")
+	saturation := flag.Float64("saturation", 1, "scale color saturation: 0 is grayscale, above 1 is more vivid")
+	hueShift := flag.Float64("hue-shift", 0, "rotate source colors around the hue wheel by this many degrees (0-360)")
+	posterizeLevels := flag.Int("posterize", 0, "reduce each color channel to this many levels (2-32) before -dither is applied; 0 disables")
+	brightness := flag.Float64("brightness", 0, "brightness offset from -1.0 to 1.0")
+	contrast := flag.Float64("contrast", 1, "contrast multiplier around the midpoint")
+	threshold := flag.Float64("threshold", 0, "render black and white, splitting at this brightness (0-1); 0 disables")
+	dither := flag.String("dither", "none", "dithering: none, floyd, atkinson or bayer")
+	edges := flag.Bool("edges", false, "draw outlines where the brightness gradient is steep")
+	edgesOnly := flag.Bool("edges-only", false, "draw only the outlines found by -edges, blank elsewhere")
+	edgeThreshold := flag.Float64("edge-threshold", 0.3, "gradient magnitude (0-1) above which -edges draws a line")
+	edgeWeight := flag.Float64("edge-weight", 1, "scale the gradient magnitude before -edge-threshold in -edges mode, drawing more outlines above 1 and fewer below; -edges-only ignores it")
+	fps := flag.Float64("fps", 0, "animation playback rate; 0 uses the GIF frame delays")
+	loop := flag.Int("loop", 1, "number of times to play an animation, -1 for infinite")
+	frameRange := flag.String("frames", "", "animation frames to play, e.g. 0-10,15")
+	page := flag.Int("page", -1, "page of a multi-page TIFF to render (default 0)")
+	noAutoOrient := flag.Bool("no-auto-orient", false, "ignore EXIF orientation and render the image as stored")
+	crop := flag.String("crop", "", "render only the region X,Y,W,H of the stored image, before any rotation")
+	rotate := flag.Int("rotate", 0, "rotate clockwise by 0, 90, 180 or 270 degrees after EXIF orientation")
+	flipH := flag.Bool("flip-h", false, "mirror the image horizontally")
+	flipV := flag.Bool("flip-v", false, "mirror the image vertically")
+	maxStdinBytes := flag.Int64("max-stdin-bytes", 100<<20, "maximum number of bytes to read from stdin or a URL")
+	maxInputPixels := flag.Int("max-input-pixels", 100_000_000, "refuse to decode images with more pixels than this; 0 disables the limit")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout for downloading an image URL")
+	userAgent := flag.String("user-agent", "", "User-Agent header sent when downloading an image URL")
+	tlsSkipVerify := flag.Bool("tls-skip-verify", false, "skip TLS certificate verification for image URLs")
+	flag.BoolVar(&verbose, "verbose", false, "log diagnostic details")
+	logFormat := flag.String("log-format", "text", "log output format: text or json (one object per line)")
+	flag.BoolVar(&quiet, "quiet", false, "suppress warnings and print errors without a timestamp")
+	mode := flag.String("mode", "ascii", "rendering mode: ascii, halfblock, braille or quarterblock")
+	brailleThreshold := flag.Float64("braille-threshold", 0.5, "brightness (0-1) at which -mode braille draws a dot")
+	workers := flag.Int("workers", 1, "number of goroutines used to render rows")
+	format := flag.String("format", "text", "output format: text, html, html-doc, svg, json, rtf, ans, png, sixel, kitty, iterm2, markdown or markdown-ansi (16 colors unless -color is set)")
+	lineEnding := flag.String("line-ending", "lf", "row terminator for text and markdown output: lf, crlf or none")
+	noTrailingNewline := flag.Bool("no-trailing-newline", false, "omit the line ending after the last row of text output, or after the closing fence of markdown")
+	caption := flag.String("caption", "", "title printed above text, markdown or markdown-ansi output")
+	captionAuto := flag.Bool("caption-auto", false, "use the input filename as -caption")
+	stats := flag.Bool("stats", false, "print a character histogram, source brightness and stage timings to stderr")
+	metadata := flag.Bool("metadata", false, "print EXIF metadata such as camera, exposure and GPS position after text, markdown or markdown-ansi output")
+	center := flag.Bool("center", false, "center text, markdown or markdown-ansi output horizontally in the terminal")
+	padding := flag.Int("padding", 0, "blank columns and rows around the output, inside any -border")
+	paddingTop := flag.Int("padding-top", 0, "blank rows above the output, overriding -padding")
+	paddingRight := flag.Int("padding-right", 0, "blank columns right of the output, overriding -padding")
+	paddingBottom := flag.Int("padding-bottom", 0, "blank rows below the output, overriding -padding")
+	paddingLeft := flag.Int("padding-left", 0, "blank columns left of the output, overriding -padding")
+	border := flag.String("border", "none", "frame the output: none, ascii, single or double")
+	output := flag.String("output", "", "write the rendering to this file instead of stdout")
+	sideBySide := flag.Bool("side-by-side", false, "render two images next to each other for comparison")
+	labels := flag.String("labels", "", "comma-separated titles printed above the -side-by-side panels")
+	diff := flag.String("diff", "", "compare the image with this one, marking the cells that changed")
+	tile := flag.String("tile", "", "lay out several images in an NxM grid of columns by rows")
+	tileGap := flag.Int("tile-gap", 1, "blank columns between -tile tiles")
+	profile := flag.String("profile", "", "size and quality preset: tiny, small, medium, large or hd; other flags override it")
+	pprofSpec := flag.String("pprof", "", "write a profile of the run: cpu:path or mem:path")
+	dryRun := flag.Bool("dry-run", false, "check the options, output path and every input image, then exit without rendering")
+	serve := flag.String("serve", "", "serve renderings over HTTP on this address, such as :8080")
+	maxUploadBytes := flag.Int64("max-upload-bytes", 10<<20, "maximum image size accepted by -serve")
+	renderTimeout := flag.Duration("render-timeout", 30*time.Second, "time limit for each -serve render")
+	stream := flag.Bool("stream", false, "print each row as soon as it is rendered")
+	frameDelay := flag.Duration("frame-delay", 0, "pause between rows with -stream, for a typewriter effect")
+	watch := flag.Bool("watch", false, "re-render the image whenever the file changes")
+	pollInterval := flag.Duration("poll-interval", 500*time.Millisecond, "how often -watch checks the file for changes")
+	dir := flag.String("dir", "", "render every matching image in this directory instead of a single image")
+	glob := flag.String("glob", "*.jpg,*.jpeg,*.png", "comma-separated file name patterns used with -dir")
+	zipFilter := flag.String("zip-filter", "", "only render ZIP archive entries whose path or name matches this pattern")
+	recursive := flag.Bool("recursive", false, "descend into subdirectories of -dir")
+	failFast := flag.Bool("fail-fast", false, "stop -dir at the first image that fails")
+	overwrite := flag.Bool("overwrite", false, "allow -output to replace an existing file")
+	versionFlag := flag.Bool("version", false, "print version and build information and exit")
+	listCharsetsFlag := flag.Bool("list-charsets", false, "print the named -charset palettes and exit")
+	previewCharsetName := flag.String("preview-charset", "", "draw a gradient bar in this named palette and exit")
+	listFormatsFlag := flag.Bool("list-formats", false, "print the supported input and output formats and exit")
+	configFile := flag.String("config", "", "read default flag values from this file instead of $ASCII_CONFIG or $HOME/.ascii.yaml")
+	autoSize := flag.Bool("auto-size", term.IsTerminal(int(os.Stdout.Fd())), "fit output to the terminal size")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: ascii [flags] image\n       ascii [flags] -dir path\n\n"+
+			"The image may be a file, a ZIP archive of images, an http(s) URL, or - to read from stdin:\n  curl -s https://example.com/photo.jpg | ascii -\n\n"+
+			"Supported input formats: %s\n\n"+
+			"Default flag values are read from $HOME/.ascii.yaml, $ASCII_CONFIG or -config, one\n"+
+			"\"flag-name: value\" per line. Flags on the command line take precedence.\n\nFlags:\n", strings.Join(inputFormats, ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *versionFlag {
+		fmt.Println(versionString())
+		return
+	}
+	if *listFormatsFlag {
+		if err := listFormats(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *listCharsetsFlag {
+		if err := listCharsets(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	setFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	if path, explicit := configPath(*configFile); path != "" {
+		if err := applyConfig(path, explicit, setFlags); err != nil {
+			log.Fatalf("Invalid config: %v", err)
+		}
+	}
+	if quiet {
+		log.SetFlags(0)
+	}
+	switch *logFormat {
+	case "text":
+	case "json":
+		jsonLogs = true
+		log.SetFlags(0)
+		log.SetOutput(jsonLogWriter{w: os.Stderr})
+	default:
+		log.Fatalf("Invalid -log-format %q: must be text or json", *logFormat)
+	}
+	if *previewCharsetName != "" {
+		cs, err := ascii.NamedCharSet(*previewCharsetName)
+		if err != nil {
+			log.Fatalf("Invalid -preview-charset: %v", err)
+		}
+		if err := previewCharset(os.Stdout, cs, max(*width, 1)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *width <= 0 || *height <= 0 {
+		log.Fatalf("Invalid dimensions %dx%d: width and height must be positive", *width, *height)
+	}
+	palette, err := ascii.NamedCharSet(*charset)
+	if err != nil {
+		log.Fatalf("Invalid -charset: %v", err)
+	}
+	if setFlags["chars"] {
+		if setFlags["charset"] {
+			log.Fatalf("-chars and -charset cannot be used together")
+		}
+		palette, err = ascii.ParseCharSet(*chars)
+		if err != nil {
+			log.Fatalf("Invalid -chars: %v", err)
+		}
+	}
+	if *asciiOnly {
+		// The default palette only trims its middle dot and full block;
+		// any other palette with non-ASCII characters was asked for
+		// explicitly and cannot be honored.
+		filtered := palette.ASCIIOnly()
+		if len(filtered) != len(palette) && (setFlags["chars"] || *charset != "standard") {
+			log.Fatalf("-ascii-only cannot be used with a palette containing non-ASCII characters")
+		}
+		palette = filtered
+	}
+	colorMode, err := ascii.ParseColorMode(*color)
+	if err != nil {
+		log.Fatalf("Invalid -color: %v", err)
+	}
+	background, err := ascii.ParseBackground(*bgColor)
+	if err != nil {
+		log.Fatalf("Invalid -bg-color: %v", err)
+	}
+	if !setFlags["format"] && *output == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+		if detected := detectGraphicsFormat(); detected != "" {
+			verbosef("Detected %s terminal, using -format %s", detected, detected)
+			*format = detected
+		}
+	}
+	encoder, err := newEncoder(*format, colorMode, background, *colorDelta)
+	if err != nil {
+		log.Fatalf("Invalid -format: %v", err)
+	}
+	if (*caption != "" || *captionAuto) && !textFormat(*format) {
+		log.Fatalf("-caption and -caption-auto need a text -format, not %s", *format)
+	}
+	if *metadata && !textFormat(*format) {
+		log.Fatalf("-metadata needs a text -format, not %s", *format)
+	}
+	ending, err := ascii.ParseLineEnding(*lineEnding)
+	if err != nil {
+		log.Fatalf("Invalid -line-ending: %v", err)
+	}
+	switch e := encoder.(type) {
+	case *ascii.TextEncoder:
+		e.LineEnding, e.NoTrailingNewline = ending, *noTrailingNewline
+	case *ascii.MarkdownEncoder:
+		e.LineEnding, e.NoTrailingNewline = ending, *noTrailingNewline
+	case *ascii.MarkdownANSIEncoder:
+		e.LineEnding, e.NoTrailingNewline = ending, *noTrailingNewline
+	}
+	borderStyle, err := ascii.ParseBorderStyle(*border)
+	if err != nil {
+		log.Fatalf("Invalid -border: %v", err)
+	}
+	pad := [4]int{*paddingTop, *paddingRight, *paddingBottom, *paddingLeft}
+	for i, name := range []string{"padding-top", "padding-right", "padding-bottom", "padding-left"} {
+		if !setFlags[name] {
+			pad[i] = *padding
+		}
+		if pad[i] < 0 {
+			log.Fatalf("Invalid -%s %d: must not be negative", name, pad[i])
+		}
+	}
+	// frameW and frameH are the columns and rows that padding and the
+	// border add around the rendering. Rows pass through the padding
+	// before the border, which therefore frames it.
+	frameW, frameH := pad[1]+pad[3], pad[0]+pad[2]
+	if borderStyle != ascii.BorderNone {
+		encoder = &ascii.BorderEncoder{Encoder: encoder, Style: borderStyle}
+		frameW, frameH = frameW+2, frameH+2
+	}
+	if pad != [4]int{} {
+		encoder = &ascii.PaddingEncoder{Encoder: encoder, Top: pad[0], Right: pad[1], Bottom: pad[2], Left: pad[3]}
+	}
+	interpolation, err := ascii.ParseInterpolation(*interp)
+	if err != nil {
+		log.Fatalf("Invalid -interp: %v", err)
+	}
+	colorBlindness, err := ascii.ParseColorBlindness(*colorBlind)
+	if err != nil {
+		log.Fatalf("Invalid -color-blind: %v", err)
+	}
+	lumaWeights, err := ascii.ParseLumaWeights(*luma)
+	if err != nil {
+		log.Fatalf("Invalid -luma: %v", err)
+	}
+	ditherMode, err := ascii.ParseDither(*dither)
+	if err != nil {
+		log.Fatalf("Invalid -dither: %v", err)
+	}
+	// profileSize is the -profile preset size, zero without one. It is
+	// applied once -scale has been checked, which overrides it.
+	var profileSize image.Point
+	if *profile != "" {
+		preset, err := ascii.ProfileOptions(*profile)
+		if err != nil {
+			log.Fatalf("Invalid -profile: %v", err)
+		}
+		profileSize = image.Pt(preset.Width, preset.Height)
+		if !setFlags["interp"] {
+			interpolation = preset.Interpolation
+		}
+		if !setFlags["dither"] {
+			ditherMode = preset.Dither
+		}
+		if !setFlags["equalize"] {
+			*equalize = preset.Equalize
+		}
+	}
+	if *threshold < 0 || *threshold > 1 {
+		log.Fatalf("Invalid threshold %v: must be between 0 and 1", *threshold)
+	}
+	if *gamma <= 0 {
+		log.Fatalf("Invalid gamma %v: must be positive", *gamma)
+	}
+	if *posterizeLevels != 0 && (*posterizeLevels < 2 || *posterizeLevels > 32) {
+		log.Fatalf("Invalid posterize %d: must be between 2 and 32", *posterizeLevels)
+	}
+	if *sharpen < 0 || *sharpen > 5 {
+		log.Fatalf("Invalid sharpen %v: must be between 0 and 5", *sharpen)
+	}
+	if *brightness < -1 || *brightness > 1 {
+		log.Fatalf("Invalid brightness %v: must be between -1 and 1", *brightness)
+	}
+	renderMode, err := ascii.ParseRenderMode(*mode)
+	if err != nil {
+		log.Fatalf("Invalid -mode: %v", err)
+	}
+	if *asciiOnly && renderMode != ascii.ModeASCII {
+		log.Fatalf("-ascii-only cannot be used with -mode %s", *mode)
+	}
+	if *asciiOnly && borderStyle != ascii.BorderNone && borderStyle != ascii.BorderASCII {
+		log.Fatalf("-ascii-only cannot be used with -border %s", *border)
+	}
+	if *fit && *fill {
+		log.Fatalf("-fit and -fill cannot be used together")
+	}
+	if *fill && !(setFlags["width"] && setFlags["height"]) {
+		log.Fatalf("-fill requires both -width and -height")
+	}
+	if *maxWidth < 0 || *maxHeight < 0 {
+		log.Fatalf("Invalid maximum size %dx%d: must not be negative", *maxWidth, *maxHeight)
+	}
+	scaling := setFlags["scale"] || setFlags["scale-x"] || setFlags["scale-y"]
+	if scaling && (setFlags["width"] || setFlags["height"]) {
+		log.Fatalf("-scale cannot be used with -width or -height")
+	}
+	if scaling && *fill {
+		log.Fatalf("-fill requires both -width and -height")
+	}
+	if !setFlags["scale-x"] {
+		*scaleX = *scale
+	}
+	if !setFlags["scale-y"] {
+		*scaleY = *scale
+	}
+	if scaling && (*scaleX <= 0 || *scaleY <= 0) {
+		log.Fatalf("Invalid scale %vx%v: must be positive", *scaleX, *scaleY)
+	}
+	// The -profile size fills in whichever of -width and -height was not
+	// given, and the result is rendered at exactly that size. An explicit
+	// -scale overrides it, as an explicit -interp overrides the preset
+	// interpolation.
+	if profileSize != (image.Point{}) && !scaling {
+		if !setFlags["width"] {
+			*width = profileSize.X
+		}
+		if !setFlags["height"] {
+			*height = profileSize.Y
+		}
+		setFlags["width"], setFlags["height"] = true, true
+	}
+	if *fontAspect <= 0 {
+		log.Fatalf("Invalid font aspect %v: must be positive", *fontAspect)
+	}
+
+	if *output != "" && !setFlags["auto-size"] {
+		*autoSize = false
+	}
+
+	termWidth, termHeight := 0, 0
+	if *autoSize {
+		var err error
+		termWidth, termHeight, err = TerminalSize()
+		if err != nil {
+			warnf("could not detect terminal size: %v", err)
+		}
+		termWidth, termHeight = termWidth-frameW, termHeight-frameH
+	}
+
+	centerWidth := 0
+	if *center && !textFormat(*format) {
+		warnf("-center only applies to text formats, not -format %s", *format)
+	} else if *center {
+		if centerWidth, _, err = TerminalSize(); err != nil {
+			warnf("-center could not detect terminal size: %v", err)
+		}
+	}
+
+	var cropRect image.Rectangle
+	if *crop != "" {
+		if cropRect, err = parseCrop(*crop); err != nil {
+			log.Fatalf("Invalid -crop: %v", err)
+		}
+	}
+	patterns := strings.Split(*glob, ",")
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			log.Fatalf("Invalid -glob: %v", err)
+		}
+	}
+	if err := validateZipFilter(*zipFilter); err != nil {
+		log.Fatalf("Invalid -zip-filter: %v", err)
+	}
+	tileCols, tileRows := 0, 0
+	if *tile != "" {
+		if tileCols, tileRows, err = parseTile(*tile); err != nil {
+			log.Fatalf("Invalid -tile: %v", err)
+		}
+		if *tileGap < 0 {
+			log.Fatalf("Invalid -tile-gap %d: must not be negative", *tileGap)
+		}
+	}
+	if *edgeWeight < 0 {
+		log.Fatalf("Invalid -edge-weight %v: must not be negative", *edgeWeight)
+	}
+	if *saturation < 0 {
+		log.Fatalf("Invalid -saturation %v: must not be negative", *saturation)
+	}
+	if *hueShift < 0 || *hueShift > 360 {
+		log.Fatalf("Invalid -hue-shift %v: must be between 0 and 360", *hueShift)
+	}
+	if *frameDelay < 0 {
+		log.Fatalf("Invalid -frame-delay %v: must not be negative", *frameDelay)
+	}
+	if *frameDelay > 0 && !*stream {
+		warnf("-frame-delay has no effect without -stream")
+	}
+	if *watch {
+		if *pollInterval <= 0 {
+			log.Fatalf("Invalid -poll-interval %v: must be positive", *pollInterval)
+		}
+		if flag.NArg() < 1 || flag.Arg(0) == "-" || isURL(flag.Arg(0)) {
+			log.Fatalf("-watch needs a local image file")
+		}
+	}
+	if *maxInputPixels < 0 {
+		log.Fatalf("Invalid -max-input-pixels %d: must not be negative", *maxInputPixels)
+	}
+	if *dryRun && (*serve != "" || *watch) {
+		log.Fatalf("-dry-run cannot be used with -serve or -watch")
+	}
+	if *serve != "" && (*maxUploadBytes <= 0 || *renderTimeout <= 0) {
+		log.Fatalf("Invalid -serve: -max-upload-bytes and -render-timeout must be positive")
+	}
+	if *dir == "" && *serve == "" && flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *pprofSpec != "" {
+		stop, err := startProfile(*pprofSpec)
+		if err != nil {
+			log.Fatalf("Invalid -pprof: %v", err)
+		}
+		stopProfile = stop
+		defer finishProfile()
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" && *dryRun {
+		if err := checkOutput(*output, *overwrite); err != nil {
+			fatalf("Failed to create output: %v", err)
+		}
+	} else if *output != "" {
+		f, err := createOutput(*output, *overwrite)
+		if err != nil {
+			fatalf("Failed to create output: %v", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				fatalf("Failed to write output: %v", err)
+			}
+		}()
+		out = f
+	}
+
+	baseOptions := ascii.Options{
+		Fill:             *fill,
+		CharAspect:       *fontAspect,
+		Interpolation:    interpolation,
+		AutoLevels:       *autoLevels,
+		Equalize:         *equalize,
+		Luma:             lumaWeights,
+		Gamma:            *gamma,
+		Brightness:       *brightness,
+		Contrast:         contrast,
+		Threshold:        *threshold,
+		Dither:           ditherMode,
+		Edges:            *edges,
+		EdgesOnly:        *edgesOnly,
+		EdgeThreshold:    *edgeThreshold,
+		EdgeWeight:       edgeWeight,
+		Color:            colorMode,
+		Invert:           *invert,
+		Chars:            palette,
+		Mode:             renderMode,
+		BrailleThreshold: *brailleThreshold,
+		Blur:             *blur,
+		Sharpen:          *sharpen,
+		ColorBlindness:   colorBlindness,
+		Sepia:            *sepia,
+		Negative:         *negative,
+		Saturation:       saturation,
+		HueShift:         *hueShift,
+		Posterize:        *posterizeLevels,
+		Workers:          *workers,
+		Stream:           *stream,
+		RowDelay:         *frameDelay,
+		MaxInputPixels:   *maxInputPixels,
+	}
+
+	if *serve != "" {
+		opts := baseOptions
+		opts.Width = *width
+		if setFlags["height"] {
+			opts.Height = *height
+		}
+		server := &http.Server{
+			Addr: *serve,
+			Handler: newServeHandler(serveConfig{
+				options:        opts,
+				maxUploadBytes: *maxUploadBytes,
+				renderTimeout:  *renderTimeout,
+				input: inputOptions{
+					timeout:       *timeout,
+					userAgent:     *userAgent,
+					tlsSkipVerify: *tlsSkipVerify,
+				},
+			}),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		log.Printf("Serving on %s", *serve)
+		fatalf("%v", server.ListenAndServe())
+	}
+
+	// load opens and decodes an input and applies -page, -crop, the EXIF
+	// orientation, -rotate and the flips. The caller closes src.file.
+	load := func(filename string) (src loadedImage, err error) {
+		file, err := openInput(filename, inputOptions{
+			maxBytes:      *maxStdinBytes,
+			timeout:       *timeout,
+			userAgent:     *userAgent,
+			tlsSkipVerify: *tlsSkipVerify,
+		})
+		if err != nil {
+			return src, fmt.Errorf("open image: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				file.Close()
+			}
+		}()
+
+		start := time.Now()
+		orientation, err := ascii.ReadExifOrientation(file)
+		if err != nil {
+			warnf("could not read EXIF orientation: %v", err)
+			orientation = 1
+		}
+		verbosef("EXIF read: %v", time.Since(start))
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return src, fmt.Errorf("rewind image: %w", err)
+		}
+
+		if err := checkInputPixels(file, *maxInputPixels); err != nil {
+			return src, fmt.Errorf("decode image: %w", err)
+		}
+
+		start = time.Now()
+		img, imgFormat, err := ascii.Decode(file)
+		if err != nil {
+			return src, fmt.Errorf("decode image: %w", err)
+		}
+		verbosef("decode (%s): %v", imgFormat, time.Since(start))
+
+		var animation *gif.GIF
+		if imgFormat == "gif" {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return src, fmt.Errorf("rewind image: %w", err)
+			}
+			animation, err = gif.DecodeAll(file)
+			if err != nil {
+				return src, fmt.Errorf("decode GIF frames: %w", err)
+			}
+			if len(animation.Image) > 1 {
+				img = ascii.CoalesceGIF(animation)[0]
+			} else {
+				animation = nil
+			}
+		}
+
+		if imgFormat == "tiff" {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return src, fmt.Errorf("rewind image: %w", err)
+			}
+			data, err := io.ReadAll(file)
+			if err != nil {
+				return src, fmt.Errorf("read image: %w", err)
+			}
+			pages, err := ascii.TIFFPageCount(data)
+			if err != nil {
+				return src, fmt.Errorf("read TIFF pages: %w", err)
+			}
+			if *page < 0 && pages > 1 {
+				warnf("rendering page 0 of %d, use -page to select another", pages)
+			}
+			if *page > 0 {
+				img, err = ascii.DecodeTIFFPage(data, *page)
+				if err != nil {
+					return src, fmt.Errorf("decode TIFF page: %w", err)
+				}
+			}
+		}
+
+		if *noAutoOrient {
+			verbosef("EXIF orientation %d detected, not applied (-no-auto-orient)", orientation)
+			orientation = 1
+		} else {
+			verbosef("EXIF orientation %d detected, applied", orientation)
+		}
+		geometry := transform{
+			crop:        cropRect,
+			orientation: orientation,
+			rotate:      *rotate,
+			flipH:       *flipH,
+			flipV:       *flipV,
+		}
+		if img, err = geometry.apply(img); err != nil {
+			return src, err
+		}
+		return loadedImage{img: img, animation: animation, geometry: geometry, file: file}, nil
+	}
+
+	// draw renders src.img, or src.animation when it is non-nil, to out,
+	// headed by caption when it is non-empty. src.file is the input
+	// -metadata reads from; it may be nil.
+	draw := func(src loadedImage, caption string) error {
+		img, animation, file := src.img, src.animation, src.file
+
+		newWidth, newHeight := *width, *height
+		bounds := img.Bounds()
+		srcW, srcH := bounds.Dx(), max(int(float64(bounds.Dy())**fontAspect), 1)
+		fitMode := *fit || (setFlags["width"] != setFlags["height"] && !setFlags["fit"])
+		letterbox := false
+		switch {
+		case scaling:
+			newWidth, _ = proportionalDimensions(bounds.Dx(), bounds.Dy(), *scaleX, *fontAspect)
+			_, newHeight = proportionalDimensions(bounds.Dx(), bounds.Dy(), *scaleY, *fontAspect)
+		case setFlags["width"] && setFlags["height"]:
+			letterbox = *fit
+		case setFlags["height"]:
+			if fitMode {
+				newWidth, _ = ascii.FitDimensions(srcW, srcH, math.MaxInt32, newHeight)
+			}
+		case setFlags["width"]:
+			if fitMode {
+				newHeight = ascii.ScaledHeight(bounds, newWidth, *fontAspect)
+			}
+		case termWidth > 0 && termHeight > 1:
+			newWidth, newHeight = ascii.FitDimensions(srcW, srcH, termWidth, termHeight-1)
+		default:
+			newHeight = ascii.ScaledHeight(bounds, newWidth, *fontAspect)
+		}
+		if (*maxWidth > 0 && newWidth > *maxWidth) || (*maxHeight > 0 && newHeight > *maxHeight) {
+			capW, capH := math.MaxInt32, math.MaxInt32
+			if *maxWidth > 0 {
+				capW = *maxWidth
+			}
+			if *maxHeight > 0 {
+				capH = *maxHeight
+			}
+			newWidth, newHeight = ascii.FitDimensions(newWidth, newHeight, capW, capH)
+			verbosef("Output capped to %dx%d by -max-width/-max-height", newWidth, newHeight)
+		}
+		if setFlags["width"] != setFlags["height"] {
+			warnf("only one of -width and -height was set, rendering at %dx%d", newWidth, newHeight)
+		}
+
+		opts := baseOptions
+		opts.Width, opts.Height, opts.Letterbox = newWidth, newHeight, letterbox
+		opts.Encoder = encoder
+		out := out
+		if centerWidth > 0 {
+			if outputWidth := newWidth + frameW; outputWidth > centerWidth {
+				warnf("output is wider than the %d-column terminal, not centering", centerWidth)
+			} else {
+				out = &indentWriter{w: out, indent: (centerWidth - outputWidth) / 2}
+			}
+		}
+		var recorder *runeRecorder
+		var stages []ascii.StageTiming
+		if *stats && animation == nil {
+			recorder = &runeRecorder{Encoder: opts.Encoder}
+			opts.Encoder = recorder
+		}
+		if verbose || recorder != nil {
+			opts.ProgressFunc = func(stage string, elapsed time.Duration) {
+				stages = append(stages, ascii.StageTiming{Stage: stage, Elapsed: elapsed})
+				if stage == "resize" {
+					stage = fmt.Sprintf("resize (%v, %dx%d→%dx%d)", interpolation, bounds.Dx(), bounds.Dy(), newWidth, newHeight)
+				}
+				verbosef("%s: %v", stage, elapsed)
+			}
+		}
+		converter := ascii.NewConverter(opts)
+
+		if caption != "" && textFormat(*format) {
+			if err := ascii.WriteCaptionLine(out, caption, newWidth+frameW); err != nil {
+				return fmt.Errorf("write caption: %w", err)
+			}
+		}
+
+		if animation != nil {
+			if *metadata {
+				warnf("-metadata is not printed for animations")
+			}
+			selected, err := parseFrameRange(*frameRange, len(animation.Image))
+			if err != nil {
+				return fmt.Errorf("invalid -frames: %w", err)
+			}
+			if err := playAnimation(out, animation, src.geometry, converter, selected, *fps, *loop); err != nil {
+				return fmt.Errorf("play animation: %w", err)
+			}
+			return nil
+		}
+		if err := converter.RenderToWriter(img, out); err != nil {
+			return fmt.Errorf("render image: %w", err)
+		}
+		if recorder != nil {
+			summary := ascii.ComputeRenderStats(recorder.grid, opts.Chars)
+			summary.Mean, summary.StdDev = ascii.BrightnessStats(img, opts.Luma)
+			summary.Edges = recorder.edges
+			summary.Stages = stages
+			if err := ascii.PrintStats(os.Stderr, summary); err != nil {
+				return fmt.Errorf("write stats: %w", err)
+			}
+		}
+		if *metadata && file != nil {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("rewind image: %w", err)
+			}
+			tags, err := ascii.ReadExifTags(file)
+			if err != nil {
+				warnf("could not read EXIF metadata: %v", err)
+			}
+			if err := writeMetadata(out, tags, newWidth+frameW); err != nil {
+				return fmt.Errorf("write metadata: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// render loads and draws a single input.
+	render := func(filename, caption string) error {
+		src, err := load(filename)
+		if err != nil {
+			return err
+		}
+		defer src.file.Close()
+		return draw(src, caption)
+	}
+
+	// A ZIP archive input is rendered like a -dir batch of its images.
+	archive, isZip := "", false
+	if *dir == "" {
+		archive, isZip = zipArchive(flag.Arg(0))
+	}
+
+	if *dryRun {
+		files := flag.Args()
+		if *dir != "" {
+			if files, err = findImages(*dir, patterns, *recursive); err != nil {
+				fatalf("Failed to read -dir: %v", err)
+			}
+		} else if isZip {
+			if files, err = listZipImages(archive, *zipFilter); err != nil {
+				fatalf("Failed to read archive: %v", err)
+			}
+		} else if *diff != "" {
+			files = append(files[:1:1], *diff)
+		}
+		failed := 0
+		for _, name := range files {
+			src, err := load(name)
+			if err != nil {
+				log.Printf("%s: %v", name, err)
+				failed++
+				continue
+			}
+			src.file.Close()
+			bounds := src.img.Bounds()
+			fmt.Printf("%s: %dx%d pixels, ok\n", name, bounds.Dx(), bounds.Dy())
+		}
+		dest := "stdout"
+		if *output != "" {
+			dest = *output
+		}
+		fmt.Printf("Would render %d of %d images as -format %s to %s\n", len(files)-failed, len(files), *format, dest)
+		if failed > 0 {
+			exit(1)
+		}
+		return
+	}
+
+	if *dir != "" || isZip {
+		var files []string
+		if isZip {
+			files, err = listZipImages(archive, *zipFilter)
+		} else {
+			files, err = findImages(*dir, patterns, *recursive)
+		}
+		if err != nil {
+			fatalf("Failed to read inputs: %v", err)
+		}
+		failed := 0
+		for _, name := range files {
+			caption := inputCaption(name)
+			if err := render(name, caption); err != nil {
+				if *failFast {
+					fatalf("%s: %v", caption, err)
+				}
+				log.Printf("Skipping %s: %v", caption, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Printf("%d of %d images failed", failed, len(files))
+			exit(1)
+		}
+		return
+	}
+
+	if *diff != "" {
+		var grids [2][][]rune
+		var opts ascii.Options
+		for i, name := range []string{flag.Arg(0), *diff} {
+			src, err := load(name)
+			if err != nil {
+				fatalf("%s: %v", name, err)
+			}
+			src.file.Close()
+			if i == 0 {
+				// Size both renderings from the primary image so their
+				// cells line up.
+				opts = baseOptions
+				opts.Width = *width
+				if !setFlags["width"] && termWidth > 0 {
+					opts.Width = termWidth
+				}
+				opts.Height = ascii.ScaledHeight(src.img.Bounds(), opts.Width, *fontAspect)
+				if setFlags["height"] {
+					opts.Height = *height
+				}
+			}
+			if grids[i], err = ascii.NewConverter(opts).RenderRunes(src.img); err != nil {
+				fatalf("%s: %v", name, err)
+			}
+		}
+		if err := writeDiff(out, grids[0], grids[1], colorMode != ascii.ColorNone); err != nil {
+			fatalf("Failed to write diff: %v", err)
+		}
+		return
+	}
+
+	if *sideBySide {
+		if flag.NArg() != 2 {
+			fatalf("-side-by-side takes exactly two images, got %d", flag.NArg())
+		}
+		var panels [2]image.Image
+		for i, name := range flag.Args() {
+			src, err := load(name)
+			if err != nil {
+				fatalf("%s: %v", name, err)
+			}
+			src.file.Close()
+			panels[i] = src.img
+		}
+		opts := baseOptions
+		opts.Width = *width
+		if !setFlags["width"] && termWidth > 0 {
+			opts.Width = termWidth
+		}
+		if setFlags["height"] {
+			opts.Height = *height
+		}
+		opts.Encoder = encoder
+		if *labels != "" {
+			if err := writeLabels(out, strings.Split(*labels, ","), (opts.Width-1)/2); err != nil {
+				fatalf("Failed to write labels: %v", err)
+			}
+		}
+		if err := ascii.RenderSideBySide(panels[0], panels[1], out, opts); err != nil {
+			fatalf("Failed to render images: %v", err)
+		}
+		return
+	}
+
+	if *tile != "" {
+		files := flag.Args()
+		if len(files) > tileCols*tileRows {
+			warnf("%d images given, only the first %d fit a %dx%d grid", len(files), tileCols*tileRows, tileCols, tileRows)
+			files = files[:tileCols*tileRows]
+		}
+		images := make([]image.Image, len(files))
+		for i, name := range files {
+			src, err := load(name)
+			if err != nil {
+				fatalf("%s: %v", name, err)
+			}
+			src.file.Close()
+			images[i] = src.img
+		}
+
+		// Size each tile in characters, then composite at a fixed number of
+		// pixels per character so tiles map onto whole cells.
+		totalWidth := *width
+		if !setFlags["width"] && termWidth > 0 {
+			totalWidth = termWidth
+		}
+		gapY := (*tileGap + 1) / 2
+		tileW := (totalWidth - *tileGap*(tileCols-1)) / tileCols
+		tileH := ascii.ScaledHeight(images[0].Bounds(), max(tileW, 1), *fontAspect)
+		if setFlags["height"] {
+			tileH = (*height - gapY*(tileRows-1)) / tileRows
+		}
+		if tileW < 1 || tileH < 1 {
+			fatalf("Invalid -tile: %dx%d tiles do not fit in %d columns", tileCols, tileRows, totalWidth)
+		}
+		const pixelsPerChar = 8
+		cellH := max(int(math.Round(pixelsPerChar / *fontAspect)), 1)
+		composite := ascii.TileImages(images, tileCols, tileRows, tileW*pixelsPerChar, tileH*cellH)
+
+		encoder = &gapEncoder{Encoder: encoder, tileW: tileW, tileH: tileH, gapX: *tileGap, gapY: gapY}
+		// The gaps widen the output like the frame does, so count them
+		// when centering the caption and the rendering.
+		frameW += *tileGap * (tileCols - 1)
+		*width, *height = tileW*tileCols, tileH*tileRows
+		setFlags["width"], setFlags["height"] = true, true
+		*fit = false
+		if err := draw(loadedImage{img: composite}, *caption); err != nil {
+			fatalf("Failed to render tiles: %v", err)
+		}
+		return
+	}
+
+	filename := flag.Args()[0]
+	if *captionAuto && *caption == "" {
+		*caption = filename
+	}
+	if *watch {
+		err := watchFile(out, filename, *pollInterval, func() error {
+			return render(filename, *caption)
+		})
+		if err != nil {
+			fatalf("%s: %v", filename, err)
+		}
+		return
+	}
+	if err := render(filename, *caption); err != nil {
+		log.Printf("%s: %v", filename, err)
+		if errors.Is(err, ascii.ErrUnsupportedFormat) {
+			exit(exitUnsupportedFormat)
+		}
+		exit(1)
+	}
+}
+
+// indentWriter prefixes every line written through it with indent spaces
+// to implement -center.
+type indentWriter struct {
+	w       io.Writer
+	indent  int
+	midLine bool
+}
+
+func (iw *indentWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if !iw.midLine && iw.indent > 0 {
+			if _, err := io.WriteString(iw.w, strings.Repeat(" ", iw.indent)); err != nil {
+				return n, err
+			}
+		}
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+		}
+		written, err := iw.w.Write(line)
+		n += written
+		if err != nil {
+			return n, err
+		}
+		iw.midLine = line[len(line)-1] != '\n'
+		p = p[len(line):]
+	}
+	return n, nil
+}
+
+// writeLabels prints one title centered over each panel of a -side-by-side
+// rendering, truncating long titles to the panel width.
+func writeLabels(w io.Writer, labels []string, panelWidth int) error {
+	var sb strings.Builder
+	for i := 0; i < 2; i++ {
+		label := ""
+		if i < len(labels) {
+			label = strings.TrimSpace(labels[i])
+		}
+		runes := []rune(label)
+		if len(runes) > panelWidth {
+			runes = runes[:panelWidth]
+		}
+		pad := panelWidth - len(runes)
+		sb.WriteString(strings.Repeat(" ", pad/2) + string(runes) + strings.Repeat(" ", pad-pad/2))
+		if i == 0 {
+			sb.WriteString(" ")
+		}
+	}
+	_, err := io.WriteString(w, strings.TrimRight(sb.String(), " ")+"\n")
+	return err
+}
+
+// loadedImage is a decoded input ready to render.
+type loadedImage struct {
+	img       image.Image
+	animation *gif.GIF
+	geometry  transform // applied to img, still to apply to animation frames
+	file      io.ReadSeekCloser
+}
+
+// findImages lists the files under root whose base name matches one of the
+// glob patterns, ignoring case. Subdirectories are only searched when
+// recursive is set.
+func findImages(root string, patterns []string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := strings.ToLower(d.Name())
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(strings.ToLower(strings.TrimSpace(pattern)), name); ok {
+				files = append(files, path)
+				break
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// checkOutput reports whether createOutput would succeed, without creating
+// or truncating path: it refuses an existing file unless overwrite is set,
+// and checks the directory is writable by creating and removing a probe.
+func checkOutput(path string, overwrite bool) error {
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists, use -overwrite to replace it", path)
+	}
+	probe, err := os.CreateTemp(filepath.Dir(path), ".ascii-dry-run-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// createOutput opens path for writing. Unless overwrite is set it refuses to
+// replace an existing file.
+func createOutput(path string, overwrite bool) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("%s already exists, use -overwrite to replace it", path)
+	}
+	return f, err
+}
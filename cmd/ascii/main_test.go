@@ -0,0 +1,109 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestMain runs the command itself when ASCII_TEST_MAIN is set, so tests
+// can exercise flag handling by running the test binary with a command line.
+func TestMain(m *testing.M) {
+	if os.Getenv("ASCII_TEST_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs the command with args and a home directory holding no
+// config file, and returns what it wrote to stdout and stderr.
+func runMain(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "ASCII_TEST_MAIN=1", "ASCII_CONFIG=", "HOME="+t.TempDir())
+	var out, errOut strings.Builder
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+	err = cmd.Run()
+	return out.String(), errOut.String(), err
+}
+
+// writePNG writes a white w×h PNG to a temporary directory and returns its
+// path.
+func writePNG(t *testing.T, w, h int) string {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	path := filepath.Join(t.TempDir(), "white.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProfileSize(t *testing.T) {
+	input := writePNG(t, 100, 50)
+	for _, tc := range []struct {
+		name                string
+		args                []string
+		wantCols, wantLines int
+	}{
+		{"preset size", []string{"-profile", "tiny"}, 40, 20},
+		{"-width overrides the preset width", []string{"-profile", "tiny", "-width", "50"}, 50, 20},
+		{"-height overrides the preset height", []string{"-profile", "tiny", "-height", "10"}, 40, 10},
+		{"-scale overrides the preset size", []string{"-profile", "tiny", "-scale", "0.1"}, 10, 3},
+	} {
+		stdout, stderr, err := runMain(t, append(tc.args, "-quiet", input)...)
+		if err != nil {
+			t.Errorf("%s: %v\n%s", tc.name, err, stderr)
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+		if len(lines) != tc.wantLines || utf8.RuneCountInString(lines[0]) != tc.wantCols {
+			t.Errorf("%s: rendered %d lines of %d columns, want %d of %d", tc.name, len(lines), utf8.RuneCountInString(lines[0]), tc.wantLines, tc.wantCols)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-profile", "tiny", "-scale", "0.1", "-width", "50"},
+		{"-profile", "tiny", "-fill"},
+	} {
+		if _, _, err := runMain(t, append(args, "-quiet", input)...); err == nil {
+			t.Errorf("%s: succeeded, want an error", strings.Join(args, " "))
+		}
+	}
+}
+
+func TestCenterFormats(t *testing.T) {
+	input := writePNG(t, 8, 8)
+	for _, tc := range []struct {
+		format   string
+		wantWarn bool
+	}{
+		{"text", false},
+		{"markdown", false},
+		{"markdown-ansi", false},
+		{"html", true},
+	} {
+		_, stderr, err := runMain(t, "-center", "-format", tc.format, "-width", "4", "-height", "2", input)
+		if err != nil {
+			t.Errorf("-format %s: %v\n%s", tc.format, err, stderr)
+			continue
+		}
+		if got := strings.Contains(stderr, "-center only applies"); got != tc.wantWarn {
+			t.Errorf("-format %s: stderr %q, want the -center warning: %v", tc.format, stderr, tc.wantWarn)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// metadataFields lists the EXIF tags printed by -metadata, in order.
+var metadataFields = []struct {
+	label string
+	tag   uint16
+}{
+	{"DateTime", ascii.ExifTagDateTime},
+	{"Make", ascii.ExifTagMake},
+	{"Model", ascii.ExifTagModel},
+	{"ExposureTime", ascii.ExifTagExposureTime},
+	{"FNumber", ascii.ExifTagFNumber},
+	{"ISOSpeedRatings", ascii.ExifTagISOSpeedRatings},
+	{"FocalLength", ascii.ExifTagFocalLength},
+	{"GPSLatitude", ascii.ExifTagGPSLatitude},
+	{"GPSLongitude", ascii.ExifTagGPSLongitude},
+}
+
+// writeMetadata prints the tags present in tags as an aligned key-value
+// table, cutting lines to width columns.
+func writeMetadata(w io.Writer, tags map[uint16]any, width int) error {
+	var keys, values []string
+	for _, field := range metadataFields {
+		if value, ok := formatExifTag(field.tag, tags); ok {
+			keys = append(keys, field.label)
+			values = append(values, value)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	keyWidth := 0
+	for _, key := range keys {
+		keyWidth = max(keyWidth, len(key))
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat("-", width) + "\n")
+	for i, key := range keys {
+		line := []rune(fmt.Sprintf("%-*s  %s", keyWidth, key, values[i]))
+		if len(line) > width {
+			line = line[:width]
+		}
+		sb.WriteString(string(line) + "\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatExifTag renders the value of tag for display.
+func formatExifTag(tag uint16, tags map[uint16]any) (string, bool) {
+	value, ok := tags[tag]
+	if !ok {
+		return "", false
+	}
+	switch tag {
+	case ascii.ExifTagExposureTime:
+		if v, ok := value.(float64); ok && v > 0 {
+			if v < 1 {
+				return fmt.Sprintf("1/%.0f s", 1/v), true
+			}
+			return fmt.Sprintf("%g s", v), true
+		}
+	case ascii.ExifTagFNumber:
+		if v, ok := value.(float64); ok {
+			return fmt.Sprintf("f/%.1f", v), true
+		}
+	case ascii.ExifTagFocalLength:
+		if v, ok := value.(float64); ok {
+			return fmt.Sprintf("%g mm", v), true
+		}
+	case ascii.ExifTagGPSLatitude, ascii.ExifTagGPSLongitude:
+		dms, ok := value.([]float64)
+		if !ok || len(dms) != 3 {
+			break
+		}
+		ref, _ := tags[tag-1].(string)
+		degrees := dms[0] + dms[1]/60 + dms[2]/3600
+		return strings.TrimSpace(fmt.Sprintf("%.6f° %s", math.Abs(degrees), ref)), true
+	}
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case []byte:
+		return "", false
+	}
+	return fmt.Sprint(value), true
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+// checkInputPixels reads the image header of r and rejects an image with more
+// than limit pixels before it is decoded, then rewinds r. A header that
+// cannot be read is left for the decoder to report.
+func checkInputPixels(r io.ReadSeeker, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	cfg, _, err := image.DecodeConfig(r)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind image: %w", err)
+	}
+	if err == nil && int64(cfg.Width)*int64(cfg.Height) > int64(limit) {
+		return fmt.Errorf("%dx%d image exceeds %d pixels, raise -max-input-pixels to allow it", cfg.Width, cfg.Height, limit)
+	}
+	return nil
+}
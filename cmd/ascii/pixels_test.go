@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+// pngHeader returns the signature and IHDR chunk of a w×h 8-bit RGBA PNG,
+// enough for image.DecodeConfig.
+func pngHeader(w, h uint32) []byte {
+	data := []byte("\x89PNG\r\n\x1a\n")
+	chunk := []byte("IHDR")
+	chunk = binary.BigEndian.AppendUint32(chunk, w)
+	chunk = binary.BigEndian.AppendUint32(chunk, h)
+	chunk = append(chunk, 8, 6, 0, 0, 0)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(chunk)-4))
+	data = append(data, chunk...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+}
+
+func TestCheckInputPixels(t *testing.T) {
+	const limit = 1000 * 1000
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		limit   int
+		wantErr bool
+	}{
+		{"at the limit", pngHeader(1000, 1000), limit, false},
+		{"one pixel over", pngHeader(1000*1000+1, 1), limit, true},
+		{"one row over", pngHeader(1000, 1001), limit, true},
+		{"limit disabled", pngHeader(1<<30, 1<<30), 0, false},
+		{"unreadable header", []byte("not an image"), limit, false},
+	} {
+		r := bytes.NewReader(tc.data)
+		err := checkInputPixels(r, tc.limit)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: checkInputPixels = %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+		if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+			t.Errorf("%s: reader left at offset %d, want it rewound", tc.name, pos)
+		}
+	}
+}
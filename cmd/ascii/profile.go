@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+)
+
+// startProfile parses a -pprof value of the form cpu:path or mem:path and
+// creates the profile file. A CPU profile starts immediately; the returned
+// stop function ends it, or writes the heap profile, and closes the file.
+func startProfile(spec string) (stop func() error, err error) {
+	kind, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" || (kind != "cpu" && kind != "mem") {
+		return nil, fmt.Errorf("%q is not cpu:path or mem:path", spec)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "cpu" {
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		}, nil
+	}
+	return func() error {
+		// Collect garbage first so the profile shows live memory.
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}, nil
+}
+
+// stopProfile ends the -pprof profile started by main. It does nothing when
+// no profile is running.
+var stopProfile = func() error { return nil }
+
+// finishProfile calls stopProfile once, warning if the profile could not be
+// written.
+func finishProfile() {
+	stop := stopProfile
+	stopProfile = func() error { return nil }
+	if err := stop(); err != nil {
+		warnf("could not write profile: %v", err)
+	}
+}
+
+// exit ends any -pprof profile and exits with code. os.Exit skips deferred
+// calls, so main exits through here once a profile may be running.
+func exit(code int) {
+	finishProfile()
+	os.Exit(code)
+}
+
+// fatalf logs like log.Fatalf and exits through exit.
+func fatalf(format string, args ...any) {
+	log.Printf(format, args...)
+	exit(1)
+}
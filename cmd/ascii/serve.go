@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// maxServeWidth bounds the width a client may request.
+const maxServeWidth = 1000
+
+type serveConfig struct {
+	options        ascii.Options
+	maxUploadBytes int64
+	renderTimeout  time.Duration
+	input          inputOptions
+}
+
+// newServeHandler returns the -serve handler. POST /render renders the
+// multipart "image" upload and GET /render?url=... downloads the image
+// first; both accept an optional width parameter. The response is HTML,
+// JSON or plain text depending on the Accept header.
+func newServeHandler(cfg serveConfig) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/render", func(w http.ResponseWriter, r *http.Request) {
+		var src io.ReadSeekCloser
+		switch r.Method {
+		case http.MethodPost:
+			r.Body = http.MaxBytesReader(w, r.Body, cfg.maxUploadBytes)
+			file, _, err := r.FormFile("image")
+			if err != nil {
+				http.Error(w, fmt.Sprintf("read upload: %v", err), http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+			if src, err = readAllLimited(file, "upload", cfg.maxUploadBytes); err != nil {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+		case http.MethodGet:
+			url := r.URL.Query().Get("url")
+			if !isURL(url) {
+				http.Error(w, "url must be an http or https URL", http.StatusBadRequest)
+				return
+			}
+			input := cfg.input
+			input.maxBytes = cfg.maxUploadBytes
+			var err error
+			if src, err = download(url, input); err != nil {
+				http.Error(w, fmt.Sprintf("download image: %v", err), http.StatusBadGateway)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		defer src.Close()
+
+		opts := cfg.options
+		if value := r.FormValue("width"); value != "" {
+			width, err := strconv.Atoi(value)
+			if err != nil || width < 1 || width > maxServeWidth {
+				http.Error(w, fmt.Sprintf("width must be between 1 and %d", maxServeWidth), http.StatusBadRequest)
+				return
+			}
+			opts.Width = width
+		}
+		contentType := negotiate(r.Header.Get("Accept"), &opts)
+
+		img, err := decodeOriented(src, opts.MaxInputPixels)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ascii.ErrUnsupportedFormat) {
+				status = http.StatusUnsupportedMediaType
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), cfg.renderTimeout)
+		defer cancel()
+		var buf bytes.Buffer
+		if err := ascii.NewConverter(opts).RenderContext(ctx, img, &buf); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, context.DeadlineExceeded) {
+				status = http.StatusServiceUnavailable
+			}
+			http.Error(w, fmt.Sprintf("render image: %v", err), status)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		if _, err := buf.WriteTo(w); err != nil {
+			warnf("write response: %v", err)
+		}
+	})
+	return mux
+}
+
+// negotiate picks the encoder for an Accept header and returns the content
+// type of the response. Plain text is the default.
+func negotiate(accept string, opts *ascii.Options) string {
+types:
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		switch strings.TrimSpace(mediaType) {
+		case "text/html":
+			opts.Encoder = &ascii.HTMLEncoder{Document: true}
+			return "text/html; charset=utf-8"
+		case "application/json":
+			opts.Encoder = &ascii.JSONEncoder{Color: opts.Color != ascii.ColorNone}
+			return "application/json"
+		case "text/plain":
+			break types
+		}
+	}
+	opts.Encoder = &ascii.TextEncoder{Color: opts.Color}
+	return "text/plain; charset=utf-8"
+}
+
+// decodeOriented decodes an image of at most maxPixels pixels and applies its
+// EXIF orientation.
+func decodeOriented(r io.ReadSeeker, maxPixels int) (image.Image, error) {
+	orientation, err := ascii.ReadExifOrientation(r)
+	if err != nil {
+		orientation = 1
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := checkInputPixels(r, maxPixels); err != nil {
+		return nil, err
+	}
+	img, _, err := ascii.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return ascii.Orient(img, orientation), nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"io"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// runeRecorder passes rows through to Encoder and keeps their characters,
+// and a count of the edge cells among them, for -stats.
+type runeRecorder struct {
+	ascii.Encoder
+	grid  [][]rune
+	edges int
+}
+
+func (r *runeRecorder) WriteRow(w io.Writer, y int, row []ascii.Cell) error {
+	chars := make([]rune, len(row))
+	for x, cell := range row {
+		chars[x] = cell.Char
+		if cell.Edge {
+			r.edges++
+		}
+	}
+	r.grid = append(r.grid, chars)
+	return r.Encoder.WriteRow(w, y, row)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// parseTile parses a -tile value of the form NxM, columns by rows.
+func parseTile(spec string) (cols, rows int, err error) {
+	if _, err := fmt.Sscanf(spec, "%dx%d", &cols, &rows); err != nil || cols <= 0 || rows <= 0 {
+		return 0, 0, fmt.Errorf("want NxM with positive N and M, got %q", spec)
+	}
+	return cols, rows, nil
+}
+
+// gapEncoder inserts blank separator columns and rows between the tiles of a
+// grid rendered as one image, passing the widened rows to Encoder.
+type gapEncoder struct {
+	ascii.Encoder
+	tileW, tileH int
+	gapX, gapY   int
+
+	cols, rows, y int
+	row           []ascii.Cell
+}
+
+func (e *gapEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.cols, e.rows, e.y = cols, rows, 0
+	tilesX, tilesY := (cols+e.tileW-1)/e.tileW, (rows+e.tileH-1)/e.tileH
+	width := cols + e.gapX*(tilesX-1)
+	e.row = make([]ascii.Cell, width)
+	return e.Encoder.Begin(w, width, rows+e.gapY*(tilesY-1))
+}
+
+func (e *gapEncoder) WriteRow(w io.Writer, y int, row []ascii.Cell) error {
+	if y > 0 && y%e.tileH == 0 {
+		for i := 0; i < e.gapY; i++ {
+			if err := e.Encoder.WriteRow(w, e.y, e.blank()); err != nil {
+				return err
+			}
+			e.y++
+		}
+	}
+	out := e.row[:0]
+	for x, cell := range row {
+		if x > 0 && x%e.tileW == 0 {
+			for i := 0; i < e.gapX; i++ {
+				out = append(out, ascii.Cell{Char: ' '})
+			}
+		}
+		out = append(out, cell)
+	}
+	err := e.Encoder.WriteRow(w, e.y, out)
+	e.y++
+	return err
+}
+
+// blank returns a row of spaces as wide as the output.
+func (e *gapEncoder) blank() []ascii.Cell {
+	for i := range e.row {
+		e.row[i] = ascii.Cell{Char: ' '}
+	}
+	return e.row
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// transform is the geometry applied to a decoded image and to every frame of
+// an animation: -crop, the EXIF orientation, -rotate and then the flips.
+type transform struct {
+	crop         image.Rectangle // empty for no -crop
+	orientation  int             // EXIF orientation
+	rotate       int             // clockwise degrees
+	flipH, flipV bool
+}
+
+// apply returns img transformed by t.
+func (t transform) apply(img image.Image) (image.Image, error) {
+	var err error
+	if !t.crop.Empty() {
+		if img, err = ascii.Crop(img, t.crop); err != nil {
+			return nil, fmt.Errorf("invalid -crop: %w", err)
+		}
+	}
+	if img, err = ascii.Rotate(ascii.Orient(img, t.orientation), t.rotate); err != nil {
+		return nil, fmt.Errorf("invalid -rotate: %w", err)
+	}
+	if t.flipH {
+		img = ascii.FlipHorizontal(img)
+	}
+	if t.flipV {
+		img = ascii.FlipVertical(img)
+	}
+	return img, nil
+}
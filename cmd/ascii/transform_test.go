@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+func TestTransformApply(t *testing.T) {
+	// A 3×2 image whose pixels are numbered 0-5 in reading order.
+	src := image.NewGray(image.Rect(0, 0, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+	for _, tc := range []struct {
+		name string
+		t    transform
+		want [][]uint8
+	}{
+		{"none", transform{}, [][]uint8{{0, 1, 2}, {3, 4, 5}}},
+		{"crop", transform{crop: image.Rect(1, 0, 3, 2)}, [][]uint8{{1, 2}, {4, 5}}},
+		{"orientation", transform{orientation: 3}, [][]uint8{{5, 4, 3}, {2, 1, 0}}},
+		{"rotate", transform{rotate: 90}, [][]uint8{{3, 0}, {4, 1}, {5, 2}}},
+		{"flip-h", transform{flipH: true}, [][]uint8{{2, 1, 0}, {5, 4, 3}}},
+		{"flip-v", transform{flipV: true}, [][]uint8{{3, 4, 5}, {0, 1, 2}}},
+		{"rotate and flip", transform{rotate: 90, flipH: true}, [][]uint8{{0, 3}, {1, 4}, {2, 5}}},
+		{"crop before rotate", transform{crop: image.Rect(0, 0, 2, 1), rotate: 270}, [][]uint8{{1}, {0}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := tc.t.apply(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := img.Bounds()
+			got := make([][]uint8, b.Dy())
+			for y := range got {
+				got[y] = make([]uint8, b.Dx())
+				for x := range got[y] {
+					got[y][x] = color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransformRejectsRotation(t *testing.T) {
+	if _, err := (transform{rotate: 45}).apply(image.NewGray(image.Rect(0, 0, 1, 1))); err == nil {
+		t.Error("rotate 45 succeeded, want an error")
+	}
+}
+
+func TestPlayAnimationTransformsFrames(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	frame := image.NewPaletted(image.Rect(0, 0, 2, 1), palette)
+	frame.Pix[1] = 1 // black, white
+	g := &gif.GIF{Image: []*image.Paletted{frame, frame}, Delay: []int{0, 0}}
+	converter := ascii.NewConverter(ascii.Options{Width: 2, Height: 1, Chars: ascii.CharSet(" @")})
+
+	var out bytes.Buffer
+	if err := playAnimation(&out, g, transform{flipH: true}, converter, []int{0, 1}, 1000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(out.String(), "\x1b[H@ \n"); got != 2 {
+		t.Errorf("output %q has %d flipped frames, want 2", out.String(), got)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// versionString describes the binary for -version, such as
+// "ascii version v1.2.3 (go1.22.0, git:abc1234, 2024-01-15T10:00:00Z)".
+func versionString() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return fmt.Sprintf("ascii version unknown (%s)", runtime.Version())
+	}
+	version := info.Main.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	details := []string{info.GoVersion}
+	var revision, buildTime string
+	modified := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.time":
+			buildTime = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision != "" {
+		revision = "git:" + revision[:min(len(revision), 7)]
+		if modified {
+			revision += "-dirty"
+		}
+		details = append(details, revision)
+	}
+	if buildTime != "" {
+		details = append(details, buildTime)
+	}
+	path := info.Main.Path
+	if path == "" {
+		path = info.Path
+	}
+	return fmt.Sprintf("ascii version %s (%s)\nmodule %s", version, strings.Join(details, ", "), path)
+}
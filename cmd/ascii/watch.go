@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// watchDebounce is how long a file must stay unchanged before -watch
+// re-renders it, so an export written in several steps renders once.
+const watchDebounce = 200 * time.Millisecond
+
+// watchFile renders filename to w, then polls it every interval and
+// renders it again on a clear screen whenever its size or modification
+// time changes. It returns only when render or the polling fails; SIGINT
+// restores the cursor and exits.
+func watchFile(w io.Writer, filename string, interval time.Duration, render func() error) error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	if _, err := io.WriteString(w, "\x1b[?25l"); err != nil {
+		return err
+	}
+	defer io.WriteString(w, "\x1b[?25h")
+
+	redraw := func() error {
+		if _, err := io.WriteString(w, "\x1b[2J\x1b[H"); err != nil {
+			return err
+		}
+		if err := render(); err != nil {
+			// Keep watching: the file is often caught mid-write.
+			log.Printf("%s: %v", filename, err)
+		}
+		return nil
+	}
+
+	last, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if err := redraw(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var changedAt time.Time
+	for {
+		select {
+		case <-interrupt:
+			io.WriteString(w, "\x1b[?25h\n")
+			exit(130)
+		case now := <-ticker.C:
+			info, err := os.Stat(filename)
+			if err != nil {
+				// The file may be replaced rather than rewritten; wait for
+				// it to reappear.
+				continue
+			}
+			if !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+				last, changedAt = info, now
+				continue
+			}
+			if !changedAt.IsZero() && now.Sub(changedAt) >= watchDebounce {
+				changedAt = time.Time{}
+				if err := redraw(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// zipImageExtensions are the archive entries treated as images.
+var zipImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp", ".tif", ".tiff"}
+
+// zipArchive reports whether an input names a ZIP archive, either as
+// zip://path or by a .zip extension, and returns the archive path.
+func zipArchive(name string) (string, bool) {
+	if archive, ok := strings.CutPrefix(name, "zip://"); ok {
+		return archive, !strings.Contains(archive, "!/")
+	}
+	return name, strings.EqualFold(path.Ext(name), ".zip")
+}
+
+// zipEntryName returns the input name openInput resolves to entry of
+// archive.
+func zipEntryName(archive, entry string) string {
+	return "zip://" + archive + "!/" + entry
+}
+
+// listZipImages returns the input names of the image entries of archive, in
+// archive order. A non-empty filter keeps only entries whose path or base
+// name matches it.
+func listZipImages(archive, filter string) ([]string, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var names []string
+	for _, f := range r.File {
+		if zipEntryMatches(f, filter) {
+			names = append(names, zipEntryName(archive, f.Name))
+		}
+	}
+	return names, nil
+}
+
+// zipEntryMatches reports whether f is an image entry whose path or base
+// name matches filter, which matches everything when empty.
+func zipEntryMatches(f *zip.File, filter string) bool {
+	if f.FileInfo().IsDir() || !isZipImage(f.Name) {
+		return false
+	}
+	if filter == "" {
+		return true
+	}
+	full, _ := path.Match(filter, f.Name)
+	base, _ := path.Match(filter, path.Base(f.Name))
+	return full || base
+}
+
+func isZipImage(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range zipImageExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// openZipEntry reads an entry named by zipEntryName into memory so that it
+// can be seeked like a file.
+func openZipEntry(name string, maxBytes int64) (io.ReadSeekCloser, error) {
+	archive, entry, _ := strings.Cut(strings.TrimPrefix(name, "zip://"), "!/")
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	f, err := r.Open(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readAllLimited(f, entry, maxBytes)
+}
+
+// inputCaption is the caption of a batch input: the entry path for archive
+// entries and the file name otherwise.
+func inputCaption(name string) string {
+	if _, entry, ok := strings.Cut(name, "!/"); ok && strings.HasPrefix(name, "zip://") {
+		return entry
+	}
+	return name
+}
+
+// validateZipFilter reports a malformed -zip-filter pattern.
+func validateZipFilter(filter string) error {
+	if _, err := path.Match(filter, ""); err != nil {
+		return fmt.Errorf("%q: %w", filter, err)
+	}
+	return nil
+}
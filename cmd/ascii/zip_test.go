@@ -0,0 +1,104 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AbilityJLR/ascii"
+)
+
+// writeZip writes an archive holding a white PNG under each of images and
+// the text "hello" under each of others, or a directory for names ending in
+// a slash, and returns its path.
+func writeZip(t *testing.T, images, others []string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for _, name := range images {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(w, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range others {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(name, "/") {
+			w.Write([]byte("hello"))
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "sprites.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestListZipImages(t *testing.T) {
+	archive := writeZip(t, []string{"a.png", "icons/b.PNG", "icons/c.png"}, []string{"readme.txt", "icons/"})
+	for _, tc := range []struct {
+		filter string
+		want   []string
+	}{
+		{"", []string{"a.png", "icons/b.PNG", "icons/c.png"}},
+		{"c.png", []string{"icons/c.png"}},
+		{"icons/*", []string{"icons/b.PNG", "icons/c.png"}},
+		{"*.gif", nil},
+	} {
+		got, err := listZipImages(archive, tc.filter)
+		if err != nil {
+			t.Fatalf("filter %q: %v", tc.filter, err)
+		}
+		var want []string
+		for _, entry := range tc.want {
+			want = append(want, zipEntryName(archive, entry))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("filter %q: listZipImages = %q, want %q", tc.filter, got, want)
+		}
+		for i, name := range got {
+			if caption := inputCaption(name); caption != tc.want[i] {
+				t.Errorf("inputCaption(%q) = %q, want %q", name, caption, tc.want[i])
+			}
+		}
+	}
+}
+
+func TestOpenZipEntry(t *testing.T) {
+	archive := writeZip(t, []string{"a.png"}, nil)
+	f, err := openZipEntry(zipEntryName(archive, "a.png"), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, format, err := ascii.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || img.Bounds().Dx() != 4 {
+		t.Errorf("decoded %s image %v, want a 4×4 png", format, img.Bounds())
+	}
+
+	if _, err := openZipEntry(zipEntryName(archive, "a.png"), 10); err == nil || !strings.Contains(err.Error(), "-max-stdin-bytes") {
+		t.Errorf("openZipEntry over the byte limit = %v, want a -max-stdin-bytes error", err)
+	}
+}
@@ -0,0 +1,104 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// ColorBlindness selects a color vision deficiency to simulate.
+type ColorBlindness int
+
+const (
+	ColorBlindNone ColorBlindness = iota
+	// ColorBlindDeuteranopia simulates missing green-sensitive cones.
+	ColorBlindDeuteranopia
+	// ColorBlindProtanopia simulates missing red-sensitive cones.
+	ColorBlindProtanopia
+	// ColorBlindTritanopia simulates missing blue-sensitive cones.
+	ColorBlindTritanopia
+)
+
+// ParseColorBlindness converts a name such as "deuteranopia" to a
+// ColorBlindness.
+func ParseColorBlindness(name string) (ColorBlindness, error) {
+	switch name {
+	case "none", "":
+		return ColorBlindNone, nil
+	case "deuteranopia":
+		return ColorBlindDeuteranopia, nil
+	case "protanopia":
+		return ColorBlindProtanopia, nil
+	case "tritanopia":
+		return ColorBlindTritanopia, nil
+	}
+	return 0, fmt.Errorf("unknown color blindness %q", name)
+}
+
+// colorBlindMatrices are the full-severity linear RGB transforms from
+// Machado, Oliveira and Fernandes, "A Physiologically-based Model for
+// Simulation of Color Vision Deficiency" (2009).
+var colorBlindMatrices = map[ColorBlindness][3][3]float64{
+	ColorBlindDeuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	ColorBlindProtanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	ColorBlindTritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// simulateColorBlindness returns img as seen with the given deficiency. The
+// transform is applied to linearized sRGB values.
+func simulateColorBlindness(img image.Image, kind ColorBlindness) image.Image {
+	m, ok := colorBlindMatrices[kind]
+	if !ok {
+		return img
+	}
+	var toLinear [256]float64
+	for i := range toLinear {
+		toLinear[i] = srgbToLinear(float64(i) / 255)
+	}
+	src := toRGBA(img)
+	dst := image.NewRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		a := src.Pix[i+3]
+		if a == 0 {
+			continue
+		}
+		// Work on straight alpha so the matrix sees the true color.
+		var lin [3]float64
+		for c := range lin {
+			lin[c] = toLinear[min(int(src.Pix[i+c])*255/int(a), 255)]
+		}
+		for c, row := range m {
+			v := row[0]*lin[0] + row[1]*lin[1] + row[2]*lin[2]
+			v = linearToSRGB(min(max(v, 0), 1)) * float64(a)
+			dst.Pix[i+c] = uint8(v + 0.5)
+		}
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
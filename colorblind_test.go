@@ -0,0 +1,40 @@
+package ascii
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestSimulateDeuteranopia(t *testing.T) {
+	// A red pixel next to a green one; both become shades of yellow.
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.SetRGBA(0, 0, color.RGBA{255, 0, 0, 255})
+	src.SetRGBA(1, 0, color.RGBA{0, 255, 0, 255})
+	got := simulateColorBlindness(src, ColorBlindDeuteranopia)
+	for x, want := range []color.RGBA{{163, 144, 0, 255}, {239, 214, 58, 255}} {
+		if c := got.At(x, 0); c != want {
+			t.Errorf("pixel %d = %v, want %v", x, c, want)
+		}
+	}
+}
+
+func TestSimulateColorBlindnessKeepsGrey(t *testing.T) {
+	grey := color.RGBA{128, 128, 128, 255}
+	for _, kind := range []ColorBlindness{ColorBlindDeuteranopia, ColorBlindProtanopia, ColorBlindTritanopia} {
+		c := simulateColorBlindness(uniform(1, 1, grey), kind).At(0, 0).(color.RGBA)
+		for _, v := range []uint8{c.R, c.G, c.B} {
+			if v < 126 || v > 130 {
+				t.Errorf("%d: grey became %v", kind, c)
+				break
+			}
+		}
+	}
+}
+
+func TestSimulateColorBlindnessNone(t *testing.T) {
+	src := uniform(1, 1, color.White)
+	if got := simulateColorBlindness(src, ColorBlindNone); got != image.Image(src) {
+		t.Error("ColorBlindNone returned a new image, want img unchanged")
+	}
+}
@@ -0,0 +1,57 @@
+package ascii
+
+import (
+	"fmt"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// ConvertFile decodes the image at path, applies its EXIF orientation and
+// renders it with opts.
+func ConvertFile(path string, opts Options) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open image: %w", err)
+	}
+	defer f.Close()
+
+	out, err := ConvertReader(f, "", opts)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return out, nil
+}
+
+// ConvertReader decodes an image from r and renders it with opts. When format
+// is non-empty the decoded format must match it, e.g. "jpeg" or "png". If r
+// is also an io.ReadSeeker its EXIF orientation is applied.
+func ConvertReader(r io.Reader, format string, opts Options) (string, error) {
+	orientation := 1
+	if rs, ok := r.(io.ReadSeeker); ok {
+		start, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", fmt.Errorf("seek image: %w", err)
+		}
+		if orientation, err = ReadExifOrientation(rs); err != nil {
+			orientation = 1
+		}
+		if _, err := rs.Seek(start, io.SeekStart); err != nil {
+			return "", fmt.Errorf("seek image: %w", err)
+		}
+	}
+
+	img, decoded, err := Decode(r)
+	if err != nil {
+		return "", fmt.Errorf("decode image: %w", err)
+	}
+	if format != "" && decoded != format {
+		return "", fmt.Errorf("%w: expected %s image, got %s", ErrUnsupportedFormat, format, decoded)
+	}
+	return NewConverter(opts).Render(Orient(img, orientation))
+}
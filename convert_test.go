@@ -0,0 +1,30 @@
+package ascii
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestConvertWebP(t *testing.T) {
+	const width, height = 40, 20
+	f, err := os.Open("testdata/blue-purple-pink.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	out, err := ConvertReader(f, "webp", Options{Width: width, Height: height})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "\n"); n != height {
+		t.Errorf("got %d lines, want %d", n, height)
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if n := utf8.RuneCountInString(line); n > width {
+			t.Errorf("line %d has %d runes, want at most %d", i, n, width)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ascii
+
+import (
+	"context"
+	"image"
+)
+
+// RenderRunes renders img at the converter's size and returns the
+// characters of each row, without color.
+func (c *Converter) RenderRunes(img image.Image) ([][]rune, error) {
+	rows, width, height, err := c.rowRenderer(context.Background(), img)
+	if err != nil {
+		return nil, err
+	}
+	cells := make([]Cell, width)
+	grid := make([][]rune, height)
+	for y := range grid {
+		rows.row(y, cells)
+		grid[y] = make([]rune, width)
+		for x, cell := range cells {
+			grid[y][x] = cell.Char
+		}
+	}
+	return grid, nil
+}
+
+// DiffRenders compares two renderings cell by cell. Cells that match hold
+// '.', cells that differ hold the character from b. The result covers the
+// larger of the two grids; cells missing from one side count as spaces.
+func DiffRenders(a, b [][]rune) [][]rune {
+	diff := make([][]rune, max(len(a), len(b)))
+	for y := range diff {
+		rowA, rowB := runeRow(a, y), runeRow(b, y)
+		diff[y] = make([]rune, max(len(rowA), len(rowB)))
+		for x := range diff[y] {
+			ra, rb := runeAt(rowA, x), runeAt(rowB, x)
+			if ra == rb {
+				diff[y][x] = '.'
+			} else {
+				diff[y][x] = rb
+			}
+		}
+	}
+	return diff
+}
+
+func runeRow(grid [][]rune, y int) []rune {
+	if y < len(grid) {
+		return grid[y]
+	}
+	return nil
+}
+
+func runeAt(row []rune, x int) rune {
+	if x < len(row) {
+		return row[x]
+	}
+	return ' '
+}
@@ -0,0 +1,147 @@
+package ascii
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dither selects how brightness is quantized to palette levels.
+type Dither int
+
+const (
+	DitherNone Dither = iota
+	// DitherFloydSteinberg diffuses quantization error to four neighbors.
+	DitherFloydSteinberg
+	// DitherAtkinson diffuses three quarters of the error to six neighbors.
+	DitherAtkinson
+	// DitherBayer applies a 4×4 ordered threshold matrix.
+	DitherBayer
+)
+
+// ParseDither converts a name such as "floyd" to a Dither.
+func ParseDither(name string) (Dither, error) {
+	switch name {
+	case "none", "":
+		return DitherNone, nil
+	case "floyd":
+		return DitherFloydSteinberg, nil
+	case "atkinson":
+		return DitherAtkinson, nil
+	case "bayer":
+		return DitherBayer, nil
+	}
+	return 0, fmt.Errorf("unknown dither %q", name)
+}
+
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+type diffusion struct {
+	dx, dy int
+	weight float64
+}
+
+var (
+	floydSteinbergKernel = []diffusion{{1, 0, 7.0 / 16}, {-1, 1, 3.0 / 16}, {0, 1, 5.0 / 16}, {1, 1, 1.0 / 16}}
+	atkinsonKernel       = []diffusion{{1, 0, 1.0 / 8}, {2, 0, 1.0 / 8}, {-1, 1, 1.0 / 8}, {0, 1, 1.0 / 8}, {1, 1, 1.0 / 8}, {0, 2, 1.0 / 8}}
+)
+
+// ditherBrightness quantizes a brightness grid to palette indices in
+// [0, levels). The grid is not modified.
+func ditherBrightness(brightness [][]float64, levels int, d Dither) [][]int {
+	indices := make([][]int, len(brightness))
+	for y := range brightness {
+		indices[y] = make([]int, len(brightness[y]))
+	}
+	if levels <= 1 {
+		return indices
+	}
+	maxIndex := float64(levels - 1)
+
+	switch d {
+	case DitherFloydSteinberg, DitherAtkinson:
+		kernel := floydSteinbergKernel
+		if d == DitherAtkinson {
+			kernel = atkinsonKernel
+		}
+		work := make([][]float64, len(brightness))
+		for y := range brightness {
+			work[y] = append([]float64(nil), brightness[y]...)
+		}
+		for y := range work {
+			for x := range work[y] {
+				v := math.Min(math.Max(work[y][x], 0), 1)
+				index := int(math.Round(v * maxIndex))
+				indices[y][x] = index
+				quantErr := v - float64(index)/maxIndex
+				for _, k := range kernel {
+					nx, ny := x+k.dx, y+k.dy
+					if ny < len(work) && nx >= 0 && nx < len(work[ny]) {
+						work[ny][nx] += quantErr * k.weight
+					}
+				}
+			}
+		}
+	case DitherBayer:
+		for y := range brightness {
+			for x, v := range brightness[y] {
+				threshold := (bayer4[y%4][x%4] + 0.5) / 16
+				indices[y][x] = clampIndex(int(v*maxIndex+threshold), levels)
+			}
+		}
+	default:
+		for y := range brightness {
+			for x, v := range brightness[y] {
+				indices[y][x] = clampIndex(int(math.Round(v*maxIndex)), levels)
+			}
+		}
+	}
+	return indices
+}
+
+func clampIndex(index, levels int) int {
+	if index < 0 {
+		return 0
+	} else if index >= levels {
+		return levels - 1
+	}
+	return index
+}
+
+// thresholdBrightness quantizes a brightness grid to the first and last of
+// levels palette indices, splitting at threshold. Dithering is only applied
+// when d is not DitherNone.
+func thresholdBrightness(brightness [][]float64, threshold float64, levels int, d Dither) [][]int {
+	var indices [][]int
+	if d == DitherNone {
+		indices = make([][]int, len(brightness))
+		for y, row := range brightness {
+			indices[y] = make([]int, len(row))
+			for x, v := range row {
+				if v >= threshold {
+					indices[y][x] = 1
+				}
+			}
+		}
+	} else {
+		// Shift the grid so the threshold sits at the two-level midpoint.
+		shifted := make([][]float64, len(brightness))
+		for y, row := range brightness {
+			shifted[y] = make([]float64, len(row))
+			for x, v := range row {
+				shifted[y][x] = v + 0.5 - threshold
+			}
+		}
+		indices = ditherBrightness(shifted, 2, d)
+	}
+	for _, row := range indices {
+		for x := range row {
+			row[x] *= levels - 1
+		}
+	}
+	return indices
+}
@@ -0,0 +1,52 @@
+package ascii
+
+import "math"
+
+// SobelGradient runs the Sobel operator over a brightness grid. Magnitudes are
+// normalized to [0, 1]; directions are the gradient angle in radians as
+// returned by math.Atan2, with y increasing downwards. Pixels outside the
+// grid are treated as copies of the nearest edge pixel.
+func SobelGradient(brightness [][]float64) (magnitude, direction [][]float64) {
+	height := len(brightness)
+	magnitude = make([][]float64, height)
+	direction = make([][]float64, height)
+	at := func(x, y int) float64 {
+		y = min(max(y, 0), height-1)
+		x = min(max(x, 0), len(brightness[y])-1)
+		return brightness[y][x]
+	}
+	for y := 0; y < height; y++ {
+		width := len(brightness[y])
+		magnitude[y] = make([]float64, width)
+		direction[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			gx := at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1) -
+				at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1)
+			gy := at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1) -
+				at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1)
+			magnitude[y][x] = math.Hypot(gx, gy) / (4 * math.Sqrt2)
+			direction[y][x] = math.Atan2(gy, gx)
+		}
+	}
+	return magnitude, direction
+}
+
+// edgeChar returns the line character that best follows an edge whose
+// gradient points in the given direction.
+func edgeChar(direction float64) rune {
+	// The edge runs perpendicular to the gradient.
+	angle := math.Mod(direction*180/math.Pi+90, 180)
+	if angle < 0 {
+		angle += 180
+	}
+	switch {
+	case angle < 22.5 || angle >= 157.5:
+		return '-'
+	case angle < 67.5:
+		return '\\'
+	case angle < 112.5:
+		return '|'
+	default:
+		return '/'
+	}
+}
@@ -0,0 +1,153 @@
+package ascii
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// Cell is one rendered character together with the pixel it was sampled from.
+type Cell struct {
+	Char rune
+	// Color is the source pixel color.
+	Color color.RGBA
+	// Brightness is the source pixel luminance in the range [0, 1].
+	Brightness float64
+	// Background is the color behind Char for modes that pack two colors
+	// into one cell. It is only meaningful when HasBackground is set.
+	Background    color.RGBA
+	HasBackground bool
+	// Edge reports that Char is a line drawn by Options.Edges.
+	Edge bool
+}
+
+// Encoder writes rendered rows in a particular output format. Begin is called
+// once before the first row and End once after the last.
+type Encoder interface {
+	Begin(w io.Writer, cols, rows int) error
+	WriteRow(w io.Writer, y int, row []Cell) error
+	End(w io.Writer) error
+}
+
+// TextEncoder writes plain text, optionally colored with ANSI escapes. A
+// color escape is only written when the color changes, and each colored row
+// ends with a single reset.
+type TextEncoder struct {
+	Color ColorMode
+	// Background, when set, paints a background color behind every
+	// character, using truecolor escapes if Color is ColorNone. Cells that
+	// carry their own background are always drawn in color.
+	Background *Background
+	// ColorDelta is the largest per-channel difference from the previous
+	// character's color that is drawn without a new escape.
+	ColorDelta int
+	// LineEnding selects what terminates each row.
+	LineEnding LineEnding
+	// NoTrailingNewline omits the line ending after the last row.
+	NoTrailingNewline bool
+
+	rows int
+}
+
+// LineEnding selects the terminator TextEncoder writes after each row.
+type LineEnding int
+
+const (
+	LineEndingLF LineEnding = iota
+	LineEndingCRLF
+	// LineEndingNone runs the rows together.
+	LineEndingNone
+)
+
+// ParseLineEnding converts a -line-ending value: lf, crlf or none.
+func ParseLineEnding(name string) (LineEnding, error) {
+	switch name {
+	case "lf", "":
+		return LineEndingLF, nil
+	case "crlf":
+		return LineEndingCRLF, nil
+	case "none":
+		return LineEndingNone, nil
+	}
+	return 0, fmt.Errorf("unknown line ending %q", name)
+}
+
+func (l LineEnding) terminator() string {
+	switch l {
+	case LineEndingCRLF:
+		return "\r\n"
+	case LineEndingNone:
+		return ""
+	}
+	return "\n"
+}
+
+func (e *TextEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.rows = rows
+	return nil
+}
+
+func (e *TextEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	diff := diffColorEncoder{delta: e.ColorDelta}
+	for _, cell := range row {
+		fg, bg := cell.Color, color.RGBA{}
+		mode := e.Color
+		if mode == ColorNone && (cell.HasBackground || e.Background != nil) {
+			mode = ColorTrueColor
+		}
+		escape := ""
+		if e.Color != ColorNone || cell.HasBackground {
+			escape = ansiForeground(mode, int(fg.R), int(fg.G), int(fg.B))
+		}
+		if cell.HasBackground {
+			bg = cell.Background
+			escape += ansiBackground(mode, int(bg.R), int(bg.G), int(bg.B))
+		} else if e.Background != nil {
+			bg = e.Background.colorFor(fg)
+			escape += ansiBackground(mode, int(bg.R), int(bg.G), int(bg.B))
+		}
+		if _, err := fmt.Fprintf(w, "%s%c", diff.escapeFor(escape, fg, bg), cell.Char); err != nil {
+			return err
+		}
+	}
+	if diff.started {
+		if _, err := io.WriteString(w, "\x1b[0m"); err != nil {
+			return err
+		}
+	}
+	if e.NoTrailingNewline && y == e.rows-1 {
+		return nil
+	}
+	_, err := io.WriteString(w, e.LineEnding.terminator())
+	return err
+}
+
+func (e *TextEncoder) End(w io.Writer) error { return nil }
+
+// diffColorEncoder suppresses color escapes that would not visibly change the
+// color set by the previous one.
+type diffColorEncoder struct {
+	delta   int
+	started bool
+	last    string
+	fg, bg  color.RGBA
+}
+
+// escapeFor returns escape, or "" when the colors it sets are within delta of
+// the escape last returned.
+func (d *diffColorEncoder) escapeFor(escape string, fg, bg color.RGBA) string {
+	if escape == "" {
+		return ""
+	}
+	if d.started && (escape == d.last || (d.within(fg, d.fg) && d.within(bg, d.bg))) {
+		return ""
+	}
+	d.started, d.last, d.fg, d.bg = true, escape, fg, bg
+	return escape
+}
+
+func (d *diffColorEncoder) within(a, b color.RGBA) bool {
+	return abs(int(a.R)-int(b.R)) <= d.delta &&
+		abs(int(a.G)-int(b.G)) <= d.delta &&
+		abs(int(a.B)-int(b.B)) <= d.delta
+}
@@ -0,0 +1,38 @@
+package ascii
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/AbilityJLR/ascii/exiforientation"
+)
+
+// Errors returned by the package, wrapped with further detail. Test for them
+// with errors.Is.
+var (
+	// ErrUnsupportedFormat means the input is not in an image format the
+	// package can decode.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrInvalidDimensions means a requested width, height or region is
+	// empty or out of range.
+	ErrInvalidDimensions = errors.New("invalid dimensions")
+	// ErrExifParseFailed means the EXIF or TIFF metadata is malformed.
+	ErrExifParseFailed = exiforientation.ErrMalformed
+	// ErrImageDecode means the image data is corrupt or truncated.
+	ErrImageDecode = errors.New("image decode failed")
+)
+
+// Decode decodes an image from r like image.Decode, wrapping failures in
+// ErrUnsupportedFormat or ErrImageDecode.
+func Decode(r io.Reader) (image.Image, string, error) {
+	img, format, err := image.Decode(r)
+	switch {
+	case errors.Is(err, image.ErrFormat):
+		return nil, "", ErrUnsupportedFormat
+	case err != nil:
+		return nil, "", fmt.Errorf("%w: %w", ErrImageDecode, err)
+	}
+	return img, format, nil
+}
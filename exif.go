@@ -0,0 +1,176 @@
+package ascii
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AbilityJLR/ascii/exiforientation"
+)
+
+// ReadExifOrientationFile opens filename and returns its EXIF orientation.
+func ReadExifOrientationFile(filename string) (int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 1, err
+	}
+	defer f.Close()
+	return ReadExifOrientation(f)
+}
+
+// ReadExifOrientation returns the EXIF orientation tag of a JPEG, TIFF or PNG
+// image read from f, starting at its current offset, or 1 when the image
+// carries no orientation. See exiforientation.Read.
+func ReadExifOrientation(f io.ReadSeeker) (int, error) {
+	return exiforientation.Read(f)
+}
+
+// EXIF tag IDs understood by ReadExifTags callers. GPS tags share the map
+// with IFD0 and Exif tags, since their IDs do not overlap.
+const (
+	ExifTagMake            = 0x010F
+	ExifTagModel           = 0x0110
+	ExifTagDateTime        = 0x0132
+	ExifTagExposureTime    = 0x829A
+	ExifTagFNumber         = 0x829D
+	ExifTagISOSpeedRatings = 0x8827
+	ExifTagFocalLength     = 0x920A
+	ExifTagGPSLatitudeRef  = 0x0001
+	ExifTagGPSLatitude     = 0x0002
+	ExifTagGPSLongitudeRef = 0x0003
+	ExifTagGPSLongitude    = 0x0004
+
+	exifTagExifIFD = 0x8769
+	exifTagGPSIFD  = 0x8825
+)
+
+// ReadExifTags returns the EXIF tags of a JPEG, TIFF or PNG image read from
+// f, keyed by tag ID. See parseExifTags for the value types. The map is
+// empty when the image carries no EXIF data.
+func ReadExifTags(f io.ReadSeeker) (map[uint16]any, error) {
+	data, err := exiforientation.ReadData(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 8 {
+		return map[uint16]any{}, nil
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%w: invalid TIFF byte order", ErrExifParseFailed)
+	}
+	return parseExifTags(data, order), nil
+}
+
+// parseExifTags decodes the tags of IFD0 and of the Exif and GPS IFDs it
+// points to. ASCII values become strings, SHORT and LONG values uint16 and
+// uint32, SLONG values int32 and rationals float64; tags with several
+// values hold slices of those types, and BYTE or UNDEFINED tags []byte.
+// Entries that point outside data are skipped.
+func parseExifTags(data []byte, order binary.ByteOrder) map[uint16]any {
+	tags := map[uint16]any{}
+	if len(data) < 8 {
+		return tags
+	}
+	ifd := order.Uint32(data[4:8])
+	parseIFD(data, order, ifd, tags)
+	for _, pointer := range []uint16{exifTagExifIFD, exifTagGPSIFD} {
+		if offset, ok := tags[pointer].(uint32); ok {
+			delete(tags, pointer)
+			parseIFD(data, order, offset, tags)
+		}
+	}
+	return tags
+}
+
+// exifTypeSizes is the size in bytes of one value of each TIFF field type.
+var exifTypeSizes = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+
+func parseIFD(data []byte, order binary.ByteOrder, offset uint32, tags map[uint16]any) {
+	if int(offset)+2 > len(data) {
+		return
+	}
+	entries := int(order.Uint16(data[offset:]))
+	for i := 0; i < entries; i++ {
+		entry := int(offset) + 2 + i*12
+		if entry+12 > len(data) {
+			return
+		}
+		tag := order.Uint16(data[entry:])
+		typ := order.Uint16(data[entry+2:])
+		count := int(order.Uint32(data[entry+4:]))
+		size, ok := exifTypeSizes[typ]
+		if !ok || count <= 0 || count > len(data)/size {
+			continue
+		}
+		value := data[entry+8 : entry+12]
+		if n := size * count; n > 4 {
+			start := int(order.Uint32(value))
+			if start < 0 || start+n > len(data) {
+				continue
+			}
+			value = data[start : start+n]
+		}
+		tags[tag] = decodeExifValue(value, order, typ, count)
+	}
+}
+
+func decodeExifValue(b []byte, order binary.ByteOrder, typ uint16, count int) any {
+	switch typ {
+	case 2:
+		return strings.TrimRight(string(b[:count]), "\x00 ")
+	case 3:
+		v := make([]uint16, count)
+		for i := range v {
+			v[i] = order.Uint16(b[i*2:])
+		}
+		if count == 1 {
+			return v[0]
+		}
+		return v
+	case 4, 9:
+		v := make([]uint32, count)
+		for i := range v {
+			v[i] = order.Uint32(b[i*4:])
+		}
+		if typ == 9 {
+			s := make([]int32, count)
+			for i := range v {
+				s[i] = int32(v[i])
+			}
+			if count == 1 {
+				return s[0]
+			}
+			return s
+		}
+		if count == 1 {
+			return v[0]
+		}
+		return v
+	case 5, 10:
+		v := make([]float64, count)
+		for i := range v {
+			num, den := order.Uint32(b[i*8:]), order.Uint32(b[i*8+4:])
+			if den == 0 {
+				continue
+			}
+			if typ == 10 {
+				v[i] = float64(int32(num)) / float64(int32(den))
+			} else {
+				v[i] = float64(num) / float64(den)
+			}
+		}
+		if count == 1 {
+			return v[0]
+		}
+		return v
+	}
+	return append([]byte(nil), b[:count]...)
+}
@@ -0,0 +1,35 @@
+package ascii
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// exifJPEG returns a JPEG stream holding only an Exif APP1 segment with the
+// given orientation tag.
+func exifJPEG(orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, 0xFF, 0xD9)
+}
+
+func TestReadExifOrientationReader(t *testing.T) {
+	got, err := ReadExifOrientation(bytes.NewReader(exifJPEG(6)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 6 {
+		t.Errorf("ReadExifOrientation = %d, want 6", got)
+	}
+}
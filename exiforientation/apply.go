@@ -0,0 +1,100 @@
+package exiforientation
+
+import "image"
+
+// Apply transforms img as described by an EXIF orientation value so that it
+// displays upright. Values outside 2–8 return img unchanged.
+func Apply(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return FlipHorizontal(img)
+	case 3:
+		return Rotate180(img)
+	case 4:
+		return FlipHorizontal(Rotate180(img))
+	case 5:
+		return FlipHorizontal(Rotate90(img))
+	case 6:
+		return Rotate90(img)
+	case 7:
+		return FlipHorizontal(Rotate270(img))
+	case 8:
+		return Rotate270(img)
+	}
+	return img
+}
+
+// Rotate90 rotates img 90° clockwise.
+func Rotate90(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, h, w))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			newX := h - (y - bounds.Min.Y) - 1
+			newY := x - bounds.Min.X
+			dst.Set(newX, newY, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// Rotate180 rotates img by 180°.
+func Rotate180(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			newX := w - (x - bounds.Min.X) - 1
+			newY := h - (y - bounds.Min.Y) - 1
+			dst.Set(newX, newY, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// Rotate270 rotates img 90° counter-clockwise.
+func Rotate270(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, h, w))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			newX := y - bounds.Min.Y
+			newY := w - (x - bounds.Min.X) - 1
+			dst.Set(newX, newY, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// FlipHorizontal mirrors img left to right.
+func FlipHorizontal(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			newX := w - (x - bounds.Min.X) - 1
+			newY := y - bounds.Min.Y
+			dst.Set(newX, newY, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+// FlipVertical mirrors img top to bottom.
+func FlipVertical(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			newX := x - bounds.Min.X
+			newY := h - (y - bounds.Min.Y) - 1
+			dst.Set(newX, newY, img.At(x, y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,76 @@
+package exiforientation
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// gray returns a w×h image whose pixels are numbered 0, 1, 2, … in reading
+// order.
+func gray(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	return img
+}
+
+// pixels returns the gray levels of img, row by row.
+func pixels(img image.Image) [][]uint8 {
+	b := img.Bounds()
+	rows := make([][]uint8, b.Dy())
+	for y := range rows {
+		rows[y] = make([]uint8, b.Dx())
+		for x := range rows[y] {
+			rows[y][x] = color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y
+		}
+	}
+	return rows
+}
+
+func TestApplyGradient(t *testing.T) {
+	src := gray(4, 1)
+	for _, tc := range []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{1, [][]uint8{{0, 1, 2, 3}}},
+		{2, [][]uint8{{3, 2, 1, 0}}},
+		{3, [][]uint8{{3, 2, 1, 0}}},
+		{4, [][]uint8{{0, 1, 2, 3}}},
+		{5, [][]uint8{{0}, {1}, {2}, {3}}},
+		{6, [][]uint8{{0}, {1}, {2}, {3}}},
+		{7, [][]uint8{{3}, {2}, {1}, {0}}},
+		{8, [][]uint8{{3}, {2}, {1}, {0}}},
+	} {
+		if got := pixels(Apply(src, tc.orientation)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Apply(%d) = %v, want %v", tc.orientation, got, tc.want)
+		}
+	}
+}
+
+func TestRotateIdentity(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 5, 7))
+	for i := 0; i < 5*7; i++ {
+		src.Set(i%5, i/5, color.RGBA{uint8(i), uint8(i * 7), uint8(255 - i), 255})
+	}
+	for _, tc := range []struct {
+		name string
+		f    func(image.Image) image.Image
+	}{
+		{"rotate90 four times", func(img image.Image) image.Image { return Rotate90(Rotate90(Rotate90(Rotate90(img)))) }},
+		{"rotate180 twice", func(img image.Image) image.Image { return Rotate180(Rotate180(img)) }},
+		{"rotate270 after rotate90", func(img image.Image) image.Image { return Rotate270(Rotate90(img)) }},
+	} {
+		img := tc.f(src)
+		got, ok := img.(*image.RGBA)
+		if !ok {
+			t.Fatalf("%s: got %T, want *image.RGBA", tc.name, img)
+		}
+		if got.Bounds() != src.Bounds() || !reflect.DeepEqual(got.Pix, src.Pix) {
+			t.Errorf("%s: result differs from the original", tc.name)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package exiforientation reads the EXIF orientation tag of JPEG, PNG and
+// TIFF images and applies it to decoded images.
+//
+// The tag takes one of eight values, describing how the stored pixels must
+// be transformed for display:
+//
+//	1  as stored
+//	2  mirrored left to right
+//	3  rotated 180°
+//	4  mirrored top to bottom (rotated 180° and mirrored left to right)
+//	5  rotated 90° clockwise and mirrored left to right (transposed)
+//	6  rotated 90° clockwise
+//	7  rotated 90° counter-clockwise and mirrored left to right (transversed)
+//	8  rotated 90° counter-clockwise
+package exiforientation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrMalformed means the EXIF or TIFF metadata could not be parsed.
+var ErrMalformed = errors.New("EXIF parse failed")
+
+// Read returns the EXIF orientation tag of a JPEG, TIFF or PNG image read
+// from f, starting at its current offset, or 1 when the image carries no
+// orientation or one outside 1 to 8. Malformed metadata is reported with an
+// error wrapping ErrMalformed.
+func Read(f io.ReadSeeker) (int, error) {
+	data, err := ReadData(f)
+	if err != nil || data == nil {
+		return 1, err
+	}
+	orient, err := tiffOrientation(data)
+	if err != nil || orient < 1 || orient > 8 {
+		return 1, err
+	}
+	return orient, nil
+}
+
+// ReadData returns the TIFF-structured EXIF block of a JPEG, TIFF or PNG
+// image read from f, starting at its current offset. For TIFF input that is
+// the whole file. It returns nil when the image carries no EXIF data.
+func ReadData(f io.ReadSeeker) ([]byte, error) {
+	start, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	var magic [2]byte
+	if _, err := io.ReadFull(f, magic[:]); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case string(magic[:]) == "II" || string(magic[:]) == "MM":
+		return io.ReadAll(f)
+	case magic[0] == 0x89 && magic[1] == 'P':
+		return readPNGExif(f)
+	case magic[0] == 0xFF && magic[1] == 0xD8:
+		return readJPEGExif(f)
+	}
+	return nil, nil
+}
+
+// readJPEGExif returns the TIFF structure of the first Exif APP1 segment in
+// a JPEG stream.
+func readJPEGExif(f io.ReadSeeker) ([]byte, error) {
+	var marker [2]byte
+	if _, err := io.ReadFull(f, marker[:]); err != nil {
+		return nil, err
+	}
+	if marker[0] != 0xFF || marker[1] != 0xD8 {
+		return nil, fmt.Errorf("%w: not a JPEG file", ErrMalformed)
+	}
+
+	for {
+		var segMarker [2]byte
+		if _, err := io.ReadFull(f, segMarker[:]); err != nil {
+			return nil, nil
+		}
+		if segMarker[0] != 0xFF {
+			return nil, fmt.Errorf("%w: invalid JPEG marker", ErrMalformed)
+		}
+		switch {
+		case segMarker[1] == 0xDA || segMarker[1] == 0xD9:
+			// Metadata segments all precede the image data.
+			return nil, nil
+		case segMarker[1] == 0x01 || segMarker[1] >= 0xD0 && segMarker[1] <= 0xD7:
+			// Standalone markers carry no length.
+			continue
+		}
+
+		var segLengthBytes [2]byte
+		if _, err := io.ReadFull(f, segLengthBytes[:]); err != nil {
+			return nil, nil
+		}
+		segLength := int(binary.BigEndian.Uint16(segLengthBytes[:])) - 2
+		if segLength < 0 {
+			return nil, fmt.Errorf("%w: invalid JPEG segment length", ErrMalformed)
+		}
+
+		if segMarker[1] != 0xE1 {
+			if _, err := f.Seek(int64(segLength), io.SeekCurrent); err != nil {
+				return nil, nil
+			}
+			continue
+		}
+
+		data := make([]byte, segLength)
+		if _, err := io.ReadFull(f, data); err != nil {
+			return nil, err
+		}
+		if len(data) >= 6 && string(data[:6]) == "Exif\x00\x00" {
+			return data[6:], nil
+		}
+	}
+}
+
+// readPNGExif returns the contents of the eXIf chunk in a PNG stream.
+func readPNGExif(r io.ReadSeeker) ([]byte, error) {
+	var signature [8]byte
+	if _, err := io.ReadFull(r, signature[:]); err != nil {
+		return nil, err
+	}
+	if string(signature[:]) != "\x89PNG\r\n\x1a\n" {
+		return nil, fmt.Errorf("%w: not a PNG file", ErrMalformed)
+	}
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, nil
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		switch string(header[4:]) {
+		case "eXIf":
+			data := make([]byte, min(length, 1<<24))
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		case "IEND":
+			return nil, nil
+		}
+		if _, err := r.Seek(length+4, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of a TIFF
+// structure. It returns 0 when the tag is absent.
+func tiffOrientation(tiffData []byte) (int, error) {
+	if len(tiffData) < 8 {
+		return 0, fmt.Errorf("%w: truncated TIFF header", ErrMalformed)
+	}
+
+	var order binary.ByteOrder
+	if string(tiffData[:2]) == "II" {
+		order = binary.LittleEndian
+	} else if string(tiffData[:2]) == "MM" {
+		order = binary.BigEndian
+	} else {
+		return 0, fmt.Errorf("%w: invalid TIFF byte order", ErrMalformed)
+	}
+
+	if order.Uint16(tiffData[2:4]) != 42 {
+		return 0, fmt.Errorf("%w: invalid TIFF header", ErrMalformed)
+	}
+
+	ifdOffset := int(order.Uint32(tiffData[4:8]))
+	if ifdOffset+2 > len(tiffData) {
+		return 0, fmt.Errorf("%w: invalid IFD offset", ErrMalformed)
+	}
+
+	numEntries := int(order.Uint16(tiffData[ifdOffset : ifdOffset+2]))
+	for i := 0; i < numEntries; i++ {
+		entryOffset := ifdOffset + 2 + i*12
+		if entryOffset+12 > len(tiffData) {
+			break
+		}
+		tag := order.Uint16(tiffData[entryOffset : entryOffset+2])
+		if tag == 0x0112 {
+			orient := order.Uint16(tiffData[entryOffset+8 : entryOffset+10])
+			return int(orient), nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,101 @@
+package exiforientation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+)
+
+// tiffOrientationData returns a TIFF structure in the given byte order
+// whose first IFD holds only an orientation tag.
+func tiffOrientationData(order binary.AppendByteOrder, orientation uint16) []byte {
+	tiff := []byte("MM")
+	if order == binary.LittleEndian {
+		tiff = []byte("II")
+	}
+	tiff = order.AppendUint16(tiff, 42)
+	tiff = order.AppendUint32(tiff, 8)
+	tiff = order.AppendUint16(tiff, 1)
+	tiff = order.AppendUint16(tiff, 0x0112)
+	tiff = order.AppendUint16(tiff, 3)
+	tiff = order.AppendUint32(tiff, 1)
+	tiff = order.AppendUint16(tiff, orientation)
+	return append(tiff, 0, 0, 0, 0, 0, 0)
+}
+
+// jpegWithExif returns a JPEG stream holding only an Exif APP1 segment
+// wrapping tiff.
+func jpegWithExif(tiff []byte) []byte {
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, 0xFF, 0xD9)
+}
+
+// pngWithExif returns a PNG stream holding only an eXIf chunk wrapping
+// tiff.
+func pngWithExif(tiff []byte) []byte {
+	data := []byte("\x89PNG\r\n\x1a\n")
+	chunk := append([]byte("eXIf"), tiff...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(tiff)))
+	data = append(data, chunk...)
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+	return append(data, "\x00\x00\x00\x00IEND\xae\x42\x60\x82"...)
+}
+
+// noExifJPEG is a JPEG stream with a comment segment and no EXIF.
+var noExifJPEG = []byte{0xFF, 0xD8, 0xFF, 0xFE, 0x00, 0x04, 'h', 'i', 0xFF, 0xD9}
+
+func TestRead(t *testing.T) {
+	badOrder := tiffOrientationData(binary.BigEndian, 6)
+	copy(badOrder, "XX")
+	truncated := jpegWithExif(tiffOrientationData(binary.BigEndian, 6))
+	truncated = truncated[:len(truncated)-10]
+
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		want    int
+		wantErr error
+	}{
+		{"jpeg big-endian", jpegWithExif(tiffOrientationData(binary.BigEndian, 6)), 6, nil},
+		{"jpeg little-endian", jpegWithExif(tiffOrientationData(binary.LittleEndian, 8)), 8, nil},
+		{"tiff", tiffOrientationData(binary.LittleEndian, 3), 3, nil},
+		{"png", pngWithExif(tiffOrientationData(binary.BigEndian, 5)), 5, nil},
+		{"jpeg without exif", noExifJPEG, 1, nil},
+		{"unknown format", []byte("GIF89a"), 1, nil},
+		{"orientation 0", jpegWithExif(tiffOrientationData(binary.BigEndian, 0)), 1, nil},
+		{"orientation 9", jpegWithExif(tiffOrientationData(binary.BigEndian, 9)), 1, nil},
+		{"orientation 0xffff", jpegWithExif(tiffOrientationData(binary.BigEndian, 0xffff)), 1, nil},
+		{"invalid byte order", jpegWithExif(badOrder), 1, ErrMalformed},
+	} {
+		got, err := Read(bytes.NewReader(tc.data))
+		if got != tc.want || !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: Read = %d, %v, want %d, %v", tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+
+	if got, err := Read(bytes.NewReader(truncated)); got != 1 || err == nil {
+		t.Errorf("truncated segment: Read = %d, %v, want 1 and an error", got, err)
+	}
+}
+
+func FuzzReadExifOrientation(f *testing.F) {
+	badOrder := tiffOrientationData(binary.BigEndian, 6)
+	copy(badOrder, "XX")
+	valid := jpegWithExif(tiffOrientationData(binary.BigEndian, 6))
+	f.Add(valid)
+	f.Add(noExifJPEG)
+	f.Add(valid[:len(valid)-10])
+	f.Add(jpegWithExif(badOrder))
+	f.Add(pngWithExif(tiffOrientationData(binary.LittleEndian, 3)))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got, _ := Read(bytes.NewReader(data))
+		if got < 1 || got > 8 {
+			t.Errorf("Read = %d, want 1 to 8", got)
+		}
+	})
+}
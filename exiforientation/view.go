@@ -0,0 +1,67 @@
+package exiforientation
+
+import (
+	"image"
+	"image/color"
+)
+
+// View returns img as it displays with the given EXIF orientation, like
+// Apply, but without copying the pixels: each At call maps its coordinates
+// back to img. Values outside 2–8 return img unchanged.
+//
+// A view saves a full-size copy when the image is only sampled, as when it
+// is downscaled; BenchmarkRenderOrientedJPEG in the ascii package measures
+// the difference. The cost is that every pixel goes through At: code that
+// reads whole images, such as draw.Draw or a filter converting to
+// *image.RGBA, loses its fast path for concrete image types and is faster
+// on the result of Apply.
+func View(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	return &view{src: img, orientation: orientation}
+}
+
+// view is the image returned by View. Its bounds start at the origin.
+type view struct {
+	src         image.Image
+	orientation int
+}
+
+func (v *view) ColorModel() color.Model {
+	return v.src.ColorModel()
+}
+
+func (v *view) Bounds() image.Rectangle {
+	b := v.src.Bounds()
+	if v.orientation >= 5 {
+		return image.Rect(0, 0, b.Dy(), b.Dx())
+	}
+	return image.Rect(0, 0, b.Dx(), b.Dy())
+}
+
+func (v *view) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(v.Bounds())) {
+		return color.RGBA{}
+	}
+	b := v.src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	var sx, sy int
+	switch v.orientation {
+	case 2:
+		sx, sy = w-1-x, y
+	case 3:
+		sx, sy = w-1-x, h-1-y
+	case 4:
+		sx, sy = x, h-1-y
+	case 5:
+		sx, sy = y, x
+	case 6:
+		sx, sy = y, h-1-x
+	case 7:
+		sx, sy = w-1-y, h-1-x
+	case 8:
+		sx, sy = w-1-y, x
+	}
+	return v.src.At(b.Min.X+sx, b.Min.Y+sy)
+}
@@ -0,0 +1,196 @@
+package ascii
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// preprocess applies the filters selected in opts to the source image.
+func preprocess(img image.Image, opts Options) image.Image {
+	if opts.Blur > 0 {
+		img = gaussianBlur(img, opts.Blur)
+	}
+	if opts.Sharpen > 0 {
+		img = unsharpMask(img, opts.Sharpen, sharpenSigma)
+	}
+	if opts.ColorBlindness != ColorBlindNone {
+		img = simulateColorBlindness(img, opts.ColorBlindness)
+	}
+	if opts.Sepia {
+		img = applySepia(img)
+	}
+	if opts.Negative {
+		img = applyNegative(img)
+	}
+	if opts.Saturation != nil && *opts.Saturation != 1 {
+		img = adjustSaturation(img, *opts.Saturation)
+	}
+	if math.Mod(opts.HueShift, 360) != 0 {
+		img = shiftHue(img, opts.HueShift)
+	}
+	if opts.Posterize >= 2 {
+		img = posterize(img, opts.Posterize)
+	}
+	return img
+}
+
+// sharpenSigma is the blur radius used by Options.Sharpen.
+const sharpenSigma = 1.0
+
+// toRGBA returns img as an *image.RGBA whose bounds start at the origin.
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	if rgba, ok := img.(*image.RGBA); ok && bounds.Min == (image.Point{}) {
+		return rgba
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// gaussianKernel returns a normalized 1D Gaussian kernel covering three
+// standard deviations either side of the center.
+func gaussianKernel(sigma float64) []float64 {
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
+
+// gaussianBlur blurs img with a separable Gaussian kernel of the given
+// standard deviation in pixels. Edge pixels are extended outward. A sigma of
+// zero or less returns img unchanged.
+func gaussianBlur(img image.Image, sigma float64) image.Image {
+	if sigma <= 0 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	kernel := gaussianKernel(sigma)
+	radius := len(kernel) / 2
+
+	// Horizontal pass into a float buffer, vertical pass into dst.
+	tmp := make([]float64, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var acc [4]float64
+			for k, weight := range kernel {
+				sx := min(max(x+k-radius, 0), w-1)
+				p := src.Pix[src.PixOffset(sx, y):]
+				for c := range acc {
+					acc[c] += float64(p[c]) * weight
+				}
+			}
+			copy(tmp[(y*w+x)*4:], acc[:])
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var acc [4]float64
+			for k, weight := range kernel {
+				sy := min(max(y+k-radius, 0), h-1)
+				p := tmp[(sy*w+x)*4:]
+				for c := range acc {
+					acc[c] += p[c] * weight
+				}
+			}
+			q := dst.Pix[dst.PixOffset(x, y):]
+			for c := range acc {
+				q[c] = uint8(min(max(acc[c]+0.5, 0), 255))
+			}
+		}
+	}
+	return dst
+}
+
+// unsharpMask sharpens img by adding amount times the difference between img
+// and a Gaussian blur of it with the given sigma.
+func unsharpMask(img image.Image, amount, sigma float64) image.Image {
+	if amount <= 0 || sigma <= 0 {
+		return img
+	}
+	src := toRGBA(img)
+	blurred := gaussianBlur(src, sigma).(*image.RGBA)
+	dst := image.NewRGBA64(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		// Premultiplied channels cannot exceed alpha, which is 0xffff for
+		// opaque pixels.
+		alpha := float64(src.Pix[i+3]) * 0x101
+		for c := 0; c < 3; c++ {
+			orig := float64(src.Pix[i+c]) * 0x101
+			v := orig + amount*(orig-float64(blurred.Pix[i+c])*0x101)
+			v = min(max(v+0.5, 0), alpha)
+			dst.Pix[i*2+c*2] = uint8(uint16(v) >> 8)
+			dst.Pix[i*2+c*2+1] = uint8(uint16(v))
+		}
+		dst.Pix[i*2+6] = src.Pix[i+3]
+		dst.Pix[i*2+7] = src.Pix[i+3]
+	}
+	return dst
+}
+
+// applySepia tones img with the standard sepia matrix.
+func applySepia(img image.Image) image.Image {
+	src := toRGBA(img)
+	dst := image.NewRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		r, g, b, a := float64(src.Pix[i]), float64(src.Pix[i+1]), float64(src.Pix[i+2]), src.Pix[i+3]
+		// The matrix is linear, so it applies to premultiplied values as
+		// long as the result is clamped to alpha rather than 255.
+		limit := float64(a)
+		dst.Pix[i] = uint8(min(0.393*r+0.769*g+0.189*b+0.5, limit))
+		dst.Pix[i+1] = uint8(min(0.349*r+0.686*g+0.168*b+0.5, limit))
+		dst.Pix[i+2] = uint8(min(0.272*r+0.534*g+0.131*b+0.5, limit))
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
+
+// applyNegative replaces every color with its complement.
+func applyNegative(img image.Image) image.Image {
+	src := toRGBA(img)
+	dst := image.NewRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		// With premultiplied alpha the complement of c is alpha - c.
+		a := src.Pix[i+3]
+		dst.Pix[i] = a - src.Pix[i]
+		dst.Pix[i+1] = a - src.Pix[i+1]
+		dst.Pix[i+2] = a - src.Pix[i+2]
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
+
+// posterize rounds every color channel of img to the nearest of levels
+// evenly spaced values from 0 to 255.
+func posterize(img image.Image, levels int) image.Image {
+	if levels < 2 {
+		return img
+	}
+	step := 255 / float64(levels-1)
+	var table [256]uint8
+	for i := range table {
+		table[i] = uint8(math.Round(float64(i)/step)*step + 0.5)
+	}
+	src := toRGBA(img)
+	dst := image.NewRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		a := src.Pix[i+3]
+		for c := 0; c < 3; c++ {
+			dst.Pix[i+c] = min(table[src.Pix[i+c]], a)
+		}
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
@@ -0,0 +1,54 @@
+package ascii
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// uniform returns a w×h image filled with c.
+func uniform(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestGaussianBlurKeepsWhite(t *testing.T) {
+	src := uniform(9, 7, color.White)
+	for _, sigma := range []float64{0.5, 1, 3, 10} {
+		blurred := gaussianBlur(src, sigma)
+		b := blurred.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if c := color.RGBAModel.Convert(blurred.At(x, y)); c != (color.RGBA{255, 255, 255, 255}) {
+					t.Fatalf("sigma %v: pixel (%d, %d) = %v, want white", sigma, x, y, c)
+				}
+			}
+		}
+	}
+}
+
+func TestGaussianBlurDisabled(t *testing.T) {
+	src := uniform(2, 2, color.Black)
+	for _, sigma := range []float64{0, -1} {
+		if got := gaussianBlur(src, sigma); got != image.Image(src) {
+			t.Errorf("sigma %v returned a new image, want img unchanged", sigma)
+		}
+	}
+}
+
+func TestApplySepia(t *testing.T) {
+	for _, tc := range []struct {
+		in, want color.RGBA
+	}{
+		{color.RGBA{255, 255, 255, 255}, color.RGBA{255, 255, 239, 255}},
+		{color.RGBA{0, 0, 0, 255}, color.RGBA{0, 0, 0, 255}},
+		{color.RGBA{100, 50, 20, 255}, color.RGBA{82, 73, 57, 255}},
+	} {
+		got := applySepia(uniform(1, 1, tc.in)).At(0, 0)
+		if got != tc.want {
+			t.Errorf("applySepia(%v) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package ascii
+
+import (
+	"image"
+	"math"
+)
+
+// adjustSaturation scales the HSL saturation of every pixel of img by
+// factor: 0 desaturates to gray and values above 1 make colors more vivid.
+func adjustSaturation(img image.Image, factor float64) image.Image {
+	return mapHSL(img, func(h, s, l float64) (float64, float64, float64) {
+		return h, min(s*factor, 1), l
+	})
+}
+
+// shiftHue rotates the hue of every pixel of img by degrees, so 180 turns
+// red into cyan and green into magenta.
+func shiftHue(img image.Image, degrees float64) image.Image {
+	return mapHSL(img, func(h, s, l float64) (float64, float64, float64) {
+		h = math.Mod(h+degrees, 360)
+		if h < 0 {
+			h += 360
+		}
+		return h, s, l
+	})
+}
+
+// mapHSL returns a copy of img with every pixel's color passed through f as
+// hue in degrees and saturation and lightness in [0, 1].
+func mapHSL(img image.Image, f func(h, s, l float64) (float64, float64, float64)) image.Image {
+	src := toRGBA(img)
+	dst := image.NewRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		a := src.Pix[i+3]
+		if a == 0 {
+			continue
+		}
+		// Work on straight colors, then premultiply the result again.
+		alpha := float64(a) / 255
+		r := float64(src.Pix[i]) / 255 / alpha
+		g := float64(src.Pix[i+1]) / 255 / alpha
+		b := float64(src.Pix[i+2]) / 255 / alpha
+		r, g, b = hslToRGB(f(rgbToHSL(r, g, b)))
+		dst.Pix[i] = uint8(math.Round(min(max(r, 0), 1) * float64(a)))
+		dst.Pix[i+1] = uint8(math.Round(min(max(g, 0), 1) * float64(a)))
+		dst.Pix[i+2] = uint8(math.Round(min(max(b, 0), 1) * float64(a)))
+		dst.Pix[i+3] = a
+	}
+	return dst
+}
+
+// rgbToHSL converts a color with channels in [0, 1] to hue in degrees and
+// saturation and lightness in [0, 1].
+func rgbToHSL(r, g, b float64) (h, s, l float64) {
+	hi, lo := max(r, g, b), min(r, g, b)
+	l = (hi + lo) / 2
+	d := hi - lo
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// hslToRGB is the inverse of rgbToHSL.
+func hslToRGB(h, s, l float64) (r, g, b float64) {
+	c := (1 - math.Abs(2*l-1)) * s
+	hp := math.Mod(h, 360) / 60
+	if hp < 0 {
+		hp += 6
+	}
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	switch {
+	case hp < 1:
+		r, g, b = c, x, 0
+	case hp < 2:
+		r, g, b = x, c, 0
+	case hp < 3:
+		r, g, b = 0, c, x
+	case hp < 4:
+		r, g, b = 0, x, c
+	case hp < 5:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	m := l - c/2
+	return r + m, g + m, b + m
+}
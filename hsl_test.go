@@ -0,0 +1,28 @@
+package ascii
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestShiftHue(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	for x, c := range []color.RGBA{{255, 0, 0, 255}, {12, 200, 99, 255}, {128, 128, 128, 255}, {40, 10, 250, 255}} {
+		src.SetRGBA(x, 0, c)
+	}
+	for _, degrees := range []float64{0, 360, -360, 720} {
+		got := toRGBA(shiftHue(src, degrees))
+		for x := 0; x < 4; x++ {
+			if got.RGBAAt(x, 0) != src.RGBAAt(x, 0) {
+				t.Errorf("shiftHue by %v: pixel %d = %v, want %v", degrees, x, got.RGBAAt(x, 0), src.RGBAAt(x, 0))
+			}
+		}
+	}
+
+	red := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	red.SetRGBA(0, 0, color.RGBA{255, 0, 0, 255})
+	if got, want := toRGBA(shiftHue(red, 180)).RGBAAt(0, 0), (color.RGBA{0, 255, 255, 255}); got != want {
+		t.Errorf("shiftHue(red, 180) = %v, want cyan %v", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package ascii
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+// HTMLEncoder writes a <pre> block with each character colored by an inline
+// style. When Document is set the block is wrapped in a complete HTML5 page.
+type HTMLEncoder struct {
+	Document bool
+}
+
+func (e *HTMLEncoder) Begin(w io.Writer, cols, rows int) error {
+	if e.Document {
+		if _, err := io.WriteString(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>ascii</title>\n</head>\n<body style=\"background:#000\">\n"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "<pre style=\"font-family:monospace;line-height:1\">\n")
+	return err
+}
+
+func (e *HTMLEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	for _, cell := range row {
+		c := cell.Color
+		if _, err := fmt.Fprintf(w, "<span style=\"color:rgb(%d,%d,%d)\">%s</span>", c.R, c.G, c.B, html.EscapeString(string(cell.Char))); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (e *HTMLEncoder) End(w io.Writer) error {
+	if _, err := io.WriteString(w, "</pre>\n"); err != nil {
+		return err
+	}
+	if e.Document {
+		_, err := io.WriteString(w, "</body>\n</html>\n")
+		return err
+	}
+	return nil
+}
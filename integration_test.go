@@ -0,0 +1,83 @@
+package ascii
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// defaultOpts renders the integration test images at a fixed size.
+var defaultOpts = Options{Width: 32, Height: 16}
+
+// ansiEscape matches an SGR escape sequence.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// checkGolden compares got with testdata/name, rewriting the file instead
+// when -update is set.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestConvertFileGolden(t *testing.T) {
+	for _, tc := range []struct {
+		input, golden string
+	}{
+		{"gradient.jpg", "gradient.jpg.golden"},
+		{"gradient.png", "gradient.png.golden"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			out, err := ConvertFile(filepath.Join("testdata", tc.input), defaultOpts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tc.golden, out)
+		})
+	}
+}
+
+func TestConvertFileColorGolden(t *testing.T) {
+	opts := defaultOpts
+	opts.Color = ColorTrueColor
+	out, err := ConvertFile("testdata/gradient.png", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ansiEscape.MatchString(out) {
+		t.Fatal("color output has no ANSI escapes")
+	}
+	// The characters must match the plain rendering once the escapes
+	// are gone.
+	checkGolden(t, "gradient.png.golden", ansiEscape.ReplaceAllString(out, ""))
+}
+
+func TestConvertFileHTMLGolden(t *testing.T) {
+	opts := defaultOpts
+	opts.Encoder = &HTMLEncoder{}
+	out, err := ConvertFile("testdata/gradient.png", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "<pre") {
+		t.Errorf("HTML output starts %.20q, want <pre", out)
+	}
+	checkGolden(t, "gradient.png.html.golden", out)
+}
@@ -0,0 +1,39 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// ITerm2Encoder writes the resized image itself, one pixel per cell, as a PNG
+// sent with the iTerm2 inline image protocol, which WezTerm also supports.
+// The image is scaled to cover the same cols×rows cells the character art
+// would.
+type ITerm2Encoder struct {
+	img        *image.RGBA
+	cols, rows int
+}
+
+func (e *ITerm2Encoder) Begin(w io.Writer, cols, rows int) error {
+	e.img = image.NewRGBA(image.Rect(0, 0, cols, rows))
+	e.cols, e.rows = cols, rows
+	return nil
+}
+
+func (e *ITerm2Encoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	drawPixelRow(e.img, y, row)
+	return nil
+}
+
+func (e *ITerm2Encoder) End(w io.Writer) error {
+	payload, err := encodePNGBase64(e.img)
+	if err != nil {
+		return err
+	}
+	size := len(payload)*3/4 - strings.Count(payload[max(len(payload)-2, 0):], "=")
+	_, err = fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a\n",
+		size, e.cols, e.rows, payload)
+	return err
+}
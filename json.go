@@ -0,0 +1,48 @@
+package ascii
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONEncoder writes a JSON array of rows, each an array of cell objects.
+// Rows are encoded as they are rendered rather than buffered. When Color is
+// set every cell also carries its r, g and b components.
+type JSONEncoder struct {
+	Color bool
+}
+
+type jsonCell struct {
+	Char       string  `json:"char"`
+	Brightness float64 `json:"brightness"`
+	R          *uint8  `json:"r,omitempty"`
+	G          *uint8  `json:"g,omitempty"`
+	B          *uint8  `json:"b,omitempty"`
+}
+
+func (e *JSONEncoder) Begin(w io.Writer, cols, rows int) error {
+	_, err := io.WriteString(w, "[\n")
+	return err
+}
+
+func (e *JSONEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	if y > 0 {
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+	cells := make([]jsonCell, len(row))
+	for i, cell := range row {
+		cells[i] = jsonCell{Char: string(cell.Char), Brightness: cell.Brightness}
+		if e.Color {
+			c := cell.Color
+			cells[i].R, cells[i].G, cells[i].B = &c.R, &c.G, &c.B
+		}
+	}
+	return json.NewEncoder(w).Encode(cells)
+}
+
+func (e *JSONEncoder) End(w io.Writer) error {
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
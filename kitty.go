@@ -0,0 +1,74 @@
+package ascii
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+)
+
+// kittyChunkSize is the largest base64 payload the Kitty protocol accepts in
+// one escape sequence.
+const kittyChunkSize = 4096
+
+// KittyEncoder writes the resized image itself, one pixel per cell, as a PNG
+// sent with the Kitty terminal graphics protocol. The terminal scales it to
+// cover the same cols×rows cells the character art would.
+type KittyEncoder struct {
+	img        *image.RGBA
+	cols, rows int
+}
+
+func (e *KittyEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.img = image.NewRGBA(image.Rect(0, 0, cols, rows))
+	e.cols, e.rows = cols, rows
+	return nil
+}
+
+func (e *KittyEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	drawPixelRow(e.img, y, row)
+	return nil
+}
+
+func (e *KittyEncoder) End(w io.Writer) error {
+	payload, err := encodePNGBase64(e.img)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(payload) || i == 0; i += kittyChunkSize {
+		chunk := payload[i:min(i+kittyChunkSize, len(payload))]
+		more := 0
+		if i+kittyChunkSize < len(payload) {
+			more = 1
+		}
+		control := fmt.Sprintf("m=%d", more)
+		if i == 0 {
+			control = fmt.Sprintf("a=T,f=100,c=%d,r=%d,%s", e.cols, e.rows, control)
+		}
+		if _, err := fmt.Fprintf(w, "\x1b_G%s;%s\x1b\\", control, chunk); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// drawPixelRow sets one pixel of img per cell to the cell's source color.
+func drawPixelRow(img *image.RGBA, y int, row []Cell) {
+	for x, cell := range row {
+		c := cell.Color
+		c.A = 0xff
+		img.SetRGBA(x, y, c)
+	}
+}
+
+// encodePNGBase64 returns img encoded as a base64 PNG.
+func encodePNGBase64(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
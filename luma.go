@@ -0,0 +1,29 @@
+package ascii
+
+import "fmt"
+
+// LumaWeights are the red, green and blue coefficients used to compute a
+// pixel's luminance. They sum to 1.
+type LumaWeights struct {
+	R, G, B float64
+}
+
+// Standard luma coefficients.
+var (
+	LumaWeights601  = LumaWeights{0.299, 0.587, 0.114}
+	LumaWeights709  = LumaWeights{0.2126, 0.7152, 0.0722}
+	LumaWeights2020 = LumaWeights{0.2627, 0.6780, 0.0593}
+)
+
+// ParseLumaWeights converts a standard number such as "601" to its weights.
+func ParseLumaWeights(name string) (LumaWeights, error) {
+	switch name {
+	case "709", "":
+		return LumaWeights709, nil
+	case "601":
+		return LumaWeights601, nil
+	case "2020":
+		return LumaWeights2020, nil
+	}
+	return LumaWeights{}, fmt.Errorf("unknown luma weights %q", name)
+}
@@ -0,0 +1,71 @@
+package ascii
+
+import (
+	"io"
+)
+
+// MarkdownEncoder writes plain text inside a fenced Markdown code block.
+type MarkdownEncoder struct {
+	// LineEnding terminates each row and fence line. The fences stay on
+	// lines of their own with LineEndingNone.
+	LineEnding LineEnding
+	// NoTrailingNewline omits the line ending after the closing fence.
+	NoTrailingNewline bool
+
+	text TextEncoder
+}
+
+func (e *MarkdownEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.text.LineEnding = e.LineEnding
+	return writeFence(w, "```", e.LineEnding)
+}
+
+func (e *MarkdownEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	return e.text.WriteRow(w, y, row)
+}
+
+func (e *MarkdownEncoder) End(w io.Writer) error {
+	return writeClosingFence(w, e.LineEnding, e.NoTrailingNewline)
+}
+
+// MarkdownANSIEncoder writes ANSI colored text inside an ```ansi fenced code
+// block, which renderers such as GitHub display in color. The LineEnding of
+// the embedded TextEncoder also terminates the fence lines, and
+// NoTrailingNewline omits the line ending after the closing fence.
+type MarkdownANSIEncoder struct {
+	TextEncoder
+}
+
+func (e *MarkdownANSIEncoder) Begin(w io.Writer, cols, rows int) error {
+	return writeFence(w, "```ansi", e.LineEnding)
+}
+
+func (e *MarkdownANSIEncoder) End(w io.Writer) error {
+	return writeClosingFence(w, e.LineEnding, e.NoTrailingNewline)
+}
+
+// writeFence writes a fence line ended with l, or with "\n" for
+// LineEndingNone.
+func writeFence(w io.Writer, fence string, l LineEnding) error {
+	if l == LineEndingNone {
+		l = LineEndingLF
+	}
+	_, err := io.WriteString(w, fence+l.terminator())
+	return err
+}
+
+// writeClosingFence writes the closing fence, first breaking the line when
+// the rows were not terminated. With noTrailingNewline the fence is not
+// terminated.
+func writeClosingFence(w io.Writer, l LineEnding, noTrailingNewline bool) error {
+	if l == LineEndingNone {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	if noTrailingNewline {
+		_, err := io.WriteString(w, "```")
+		return err
+	}
+	return writeFence(w, "```", l)
+}
@@ -0,0 +1,47 @@
+package ascii
+
+import (
+	"bytes"
+	"testing"
+)
+
+// encode runs enc over rows of plain characters.
+func encode(t *testing.T, enc Encoder, rows ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := enc.Begin(&buf, len([]rune(rows[0])), len(rows)); err != nil {
+		t.Fatal(err)
+	}
+	for y, text := range rows {
+		var row []Cell
+		for _, c := range text {
+			row = append(row, Cell{Char: c})
+		}
+		if err := enc.WriteRow(&buf, y, row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.End(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestMarkdownLineEnding(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		enc  Encoder
+		want string
+	}{
+		{"lf", &MarkdownEncoder{}, "```\nab\ncd\n```\n"},
+		{"crlf", &MarkdownEncoder{LineEnding: LineEndingCRLF}, "```\r\nab\r\ncd\r\n```\r\n"},
+		{"none", &MarkdownEncoder{LineEnding: LineEndingNone}, "```\nabcd\n```\n"},
+		{"ansi crlf", &MarkdownANSIEncoder{TextEncoder{LineEnding: LineEndingCRLF}}, "```ansi\r\nab\r\ncd\r\n```\r\n"},
+		{"no trailing newline", &MarkdownEncoder{NoTrailingNewline: true}, "```\nab\ncd\n```"},
+		{"ansi no trailing newline", &MarkdownANSIEncoder{TextEncoder{NoTrailingNewline: true}}, "```ansi\nab\ncd\n```"},
+	} {
+		if got := encode(t, tc.enc, "ab", "cd"); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
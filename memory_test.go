@@ -0,0 +1,71 @@
+package ascii
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/AbilityJLR/ascii/exiforientation"
+)
+
+// photoJPEG is a 5472×3648 (20 megapixel) JPEG, the size of a camera photo,
+// encoded on first use.
+var photoJPEG = sync.OnceValue(func() []byte {
+	src := image.NewRGBA(image.Rect(0, 0, 5472, 3648))
+	for y := 0; y < 3648; y++ {
+		for x := 0; x < 5472; x++ {
+			src.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), uint8(x ^ y), 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, nil); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+})
+
+// BenchmarkRenderOrientedJPEG decodes a 20 MP JPEG, applies EXIF orientation
+// 6 and renders it 120 columns wide, orienting through a view as Orient does
+// and through a full-size copy. B/op is the total allocation per render and
+// heap-MB the heap in use once it finishes, with the oriented image still
+// reachable.
+func BenchmarkRenderOrientedJPEG(b *testing.B) {
+	for _, tc := range []struct {
+		name   string
+		orient func(image.Image, int) image.Image
+	}{
+		{"view", Orient},
+		{"copy", exiforientation.Apply},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			data := photoJPEG()
+			c := NewConverter(Options{Width: 120})
+			var heap uint64
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				img, err := jpeg.Decode(bytes.NewReader(data))
+				if err != nil {
+					b.Fatal(err)
+				}
+				oriented := tc.orient(img, 6)
+				if err := c.RenderToWriter(oriented, io.Discard); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				var m runtime.MemStats
+				runtime.ReadMemStats(&m)
+				heap = max(heap, m.HeapAlloc)
+				runtime.KeepAlive(oriented)
+				runtime.GC()
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(heap)/(1<<20), "heap-MB")
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// RenderMode selects how source pixels are packed into character cells.
+type RenderMode int
+
+const (
+	// ModeASCII draws one pixel per cell with a palette character.
+	ModeASCII RenderMode = iota
+	// ModeHalfBlock draws two vertically stacked pixels per cell using ▀
+	// with the top pixel as foreground and the bottom as background.
+	ModeHalfBlock
+	// ModeBraille draws a 2×4 block of on/off pixels per cell using the
+	// Unicode Braille patterns (U+2800–U+28FF).
+	ModeBraille
+	// ModeQuarterBlock draws a 2×2 block per cell using quadrant block
+	// elements such as ▚ and ▟.
+	ModeQuarterBlock
+)
+
+// ParseRenderMode converts a -mode value to a RenderMode.
+func ParseRenderMode(name string) (RenderMode, error) {
+	switch name {
+	case "ascii", "":
+		return ModeASCII, nil
+	case "halfblock":
+		return ModeHalfBlock, nil
+	case "braille":
+		return ModeBraille, nil
+	case "quarterblock":
+		return ModeQuarterBlock, nil
+	}
+	return 0, fmt.Errorf("unknown mode %q", name)
+}
+
+// rowRenderer fills one row of cells at a time. Implementations must allow
+// concurrent calls for different rows.
+type rowRenderer interface {
+	row(y int, dst []Cell)
+}
+
+func newRowRenderer(img image.Image, width, height int, opts Options) rowRenderer {
+	switch opts.Mode {
+	case ModeHalfBlock:
+		return newHalfBlockRows(img, width, height, opts)
+	case ModeBraille:
+		return newBrailleRows(img, width, height, opts)
+	case ModeQuarterBlock:
+		return newQuarterBlockRows(img, width, height, opts)
+	}
+	return newASCIIRows(img, width, height, opts)
+}
+
+func rgbaAt(img image.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
+
+type asciiRows struct {
+	opts                 Options
+	chars                CharSet
+	img                  image.Image
+	brightness           [][]float64
+	levels               [][]int
+	magnitude, direction [][]float64
+}
+
+func newASCIIRows(img image.Image, width, height int, opts Options) *asciiRows {
+	r := &asciiRows{opts: opts, chars: opts.Chars}
+	if len(r.chars) == 0 {
+		r.chars = asciiChars
+	}
+
+	r.img = Resize(img, width, height, opts.Interpolation)
+	r.brightness = brightnessGrid(r.img, opts)
+	switch {
+	case opts.EdgesOnly:
+	case opts.Threshold > 0:
+		r.levels = thresholdBrightness(r.brightness, opts.Threshold, len(r.chars), opts.Dither)
+	default:
+		r.levels = ditherBrightness(r.brightness, len(r.chars), opts.Dither)
+	}
+	if opts.Edges || opts.EdgesOnly {
+		r.magnitude, r.direction = SobelGradient(r.brightness)
+	}
+	return r
+}
+
+func (r *asciiRows) row(y int, dst []Cell) {
+	for x := range dst {
+		dst[x] = Cell{
+			Char:       ' ',
+			Color:      rgbaAt(r.img, x, y),
+			Brightness: r.brightness[y][x],
+		}
+		if !r.opts.EdgesOnly {
+			dst[x].Char = r.chars.At(r.levels[y][x], r.opts.Invert)
+		}
+		if r.magnitude != nil && r.edgeMagnitude(x, y) > r.opts.EdgeThreshold {
+			dst[x].Char, dst[x].Edge = edgeChar(r.direction[y][x]), true
+		}
+	}
+}
+
+// edgeMagnitude returns the gradient magnitude at (x, y), scaled by
+// EdgeWeight when edges are mixed with brightness characters.
+func (r *asciiRows) edgeMagnitude(x, y int) float64 {
+	m := r.magnitude[y][x]
+	if r.opts.EdgeWeight != nil && !r.opts.EdgesOnly {
+		m *= *r.opts.EdgeWeight
+	}
+	return m
+}
+
+type halfBlockRows struct {
+	img        image.Image
+	brightness [][]float64
+}
+
+func newHalfBlockRows(img image.Image, width, height int, opts Options) *halfBlockRows {
+	resized := Resize(img, width, height*2, opts.Interpolation)
+	return &halfBlockRows{img: resized, brightness: brightnessGrid(resized, opts)}
+}
+
+func (r *halfBlockRows) row(y int, dst []Cell) {
+	for x := range dst {
+		dst[x] = Cell{
+			Char:          '▀',
+			Color:         rgbaAt(r.img, x, 2*y),
+			Background:    rgbaAt(r.img, x, 2*y+1),
+			HasBackground: true,
+			Brightness:    (r.brightness[2*y][x] + r.brightness[2*y+1][x]) / 2,
+		}
+	}
+}
+
+// brailleDots maps a pixel offset within a 2×4 block to its Braille dot bit.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+type brailleRows struct {
+	img        image.Image
+	brightness [][]float64
+	threshold  float64
+	invert     bool
+}
+
+func newBrailleRows(img image.Image, width, height int, opts Options) *brailleRows {
+	resized := Resize(img, width*2, height*4, opts.Interpolation)
+	threshold := opts.BrailleThreshold
+	if threshold == 0 {
+		threshold = 0.5
+	}
+	return &brailleRows{
+		img:        resized,
+		brightness: brightnessGrid(resized, opts),
+		threshold:  threshold,
+		invert:     opts.Invert,
+	}
+}
+
+func (r *brailleRows) row(y int, dst []Cell) {
+	for x := range dst {
+		pattern := rune(0x2800)
+		var sum float64
+		var red, green, blue, alpha int
+		for dy := 0; dy < 4; dy++ {
+			for dx := 0; dx < 2; dx++ {
+				px, py := 2*x+dx, 4*y+dy
+				v := r.brightness[py][px]
+				sum += v
+				if (v >= r.threshold) != r.invert {
+					pattern |= brailleDots[dy][dx]
+				}
+				c := rgbaAt(r.img, px, py)
+				red += int(c.R)
+				green += int(c.G)
+				blue += int(c.B)
+				alpha += int(c.A)
+			}
+		}
+		dst[x] = Cell{
+			Char:       pattern,
+			Color:      color.RGBA{uint8(red / 8), uint8(green / 8), uint8(blue / 8), uint8(alpha / 8)},
+			Brightness: sum / 8,
+		}
+	}
+}
+
+// quarterBlocks maps a quadrant mask (1 top-left, 2 top-right, 4 bottom-left,
+// 8 bottom-right) to the block element that fills those quadrants.
+var quarterBlocks = [16]rune{
+	' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛',
+	'▗', '▚', '▐', '▜', '▄', '▙', '▟', '█',
+}
+
+type quarterBlockRows struct {
+	img        image.Image
+	brightness [][]float64
+	color      bool
+	invert     bool
+}
+
+func newQuarterBlockRows(img image.Image, width, height int, opts Options) *quarterBlockRows {
+	resized := Resize(img, width*2, height*2, opts.Interpolation)
+	return &quarterBlockRows{
+		img:        resized,
+		brightness: brightnessGrid(resized, opts),
+		color:      opts.Color != ColorNone,
+		invert:     opts.Invert,
+	}
+}
+
+// row splits each 2×2 block into bright and dark quadrants. In color mode
+// the split is at the block's mean brightness and the two halves become the
+// foreground and background colors; otherwise it is at 0.5.
+func (r *quarterBlockRows) row(y int, dst []Cell) {
+	for x := range dst {
+		var values [4]float64
+		var colors [4]color.RGBA
+		var mean float64
+		for q := 0; q < 4; q++ {
+			px, py := 2*x+q%2, 2*y+q/2
+			values[q] = r.brightness[py][px]
+			colors[q] = rgbaAt(r.img, px, py)
+			mean += values[q] / 4
+		}
+
+		threshold := 0.5
+		if r.color {
+			threshold = mean
+		}
+		mask := 0
+		for q, v := range values {
+			if (v > threshold) != r.invert {
+				mask |= 1 << q
+			}
+		}
+		if r.color && mask == 0 {
+			mask = 15
+		}
+
+		cell := Cell{Char: quarterBlocks[mask], Brightness: mean}
+		cell.Color = averageColor(colors[:], mask, true)
+		if r.color && mask != 15 {
+			cell.Background = averageColor(colors[:], mask, false)
+			cell.HasBackground = true
+		}
+		dst[x] = cell
+	}
+}
+
+// averageColor averages the colors whose quadrant bit in mask equals set.
+func averageColor(colors []color.RGBA, mask int, set bool) color.RGBA {
+	var red, green, blue, alpha, n int
+	for q, c := range colors {
+		if (mask&(1<<q) != 0) != set {
+			continue
+		}
+		red += int(c.R)
+		green += int(c.G)
+		blue += int(c.B)
+		alpha += int(c.A)
+		n++
+	}
+	if n == 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{uint8(red / n), uint8(green / n), uint8(blue / n), uint8(alpha / n)}
+}
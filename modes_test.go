@@ -0,0 +1,70 @@
+package ascii
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEdgeWeight(t *testing.T) {
+	// A black left half next to a white right half has one vertical edge.
+	img := image.NewGray(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 4; x < 8; x++ {
+			img.SetGray(x, y, color.Gray{255})
+		}
+	}
+	ptr := func(v float64) *float64 { return &v }
+	for _, tc := range []struct {
+		name      string
+		weight    *float64
+		edgesOnly bool
+		wantEdges bool
+	}{
+		{"unset", nil, false, true},
+		{"one", ptr(1), false, true},
+		{"zero", ptr(0), false, false},
+		{"zero ignored by edges-only", ptr(0), true, true},
+	} {
+		opts := Options{Width: 8, Height: 4, Edges: true, EdgesOnly: tc.edgesOnly, EdgeThreshold: 0.3, EdgeWeight: tc.weight}
+		out, err := NewConverter(opts).Render(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(out, "|"); got != tc.wantEdges {
+			t.Errorf("%s: output %q has edges: %v, want %v", tc.name, out, got, tc.wantEdges)
+		}
+		if lines, marked := strings.Count(out, "|"), countEdges(t, opts, img); lines != marked {
+			t.Errorf("%s: %d edge lines but %d cells marked Edge", tc.name, lines, marked)
+		}
+	}
+}
+
+// edgeCounter counts the cells marked Edge.
+type edgeCounter struct {
+	TextEncoder
+	n int
+}
+
+func (e *edgeCounter) WriteRow(w io.Writer, y int, row []Cell) error {
+	for _, cell := range row {
+		if cell.Edge {
+			e.n++
+		}
+	}
+	return e.TextEncoder.WriteRow(w, y, row)
+}
+
+// countEdges renders img with opts and returns how many cells are marked
+// Edge.
+func countEdges(t *testing.T, opts Options, img image.Image) int {
+	t.Helper()
+	enc := &edgeCounter{}
+	opts.Encoder = enc
+	if _, err := NewConverter(opts).Render(img); err != nil {
+		t.Fatal(err)
+	}
+	return enc.n
+}
@@ -0,0 +1,51 @@
+package ascii
+
+import "io"
+
+// PaddingEncoder surrounds the grid written by Encoder with blank cells:
+// Top and Bottom rows above and below it and Left and Right columns beside
+// each row.
+type PaddingEncoder struct {
+	Encoder                  Encoder
+	Top, Right, Bottom, Left int
+
+	cols, rows int
+	row        []Cell
+}
+
+func (e *PaddingEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.cols, e.rows = cols, rows
+	e.row = make([]Cell, e.Left+cols+e.Right)
+	if err := e.Encoder.Begin(w, len(e.row), e.Top+rows+e.Bottom); err != nil {
+		return err
+	}
+	for y := 0; y < e.Top; y++ {
+		if err := e.Encoder.WriteRow(w, y, e.blank()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *PaddingEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	e.blank()
+	copy(e.row[e.Left:], row)
+	return e.Encoder.WriteRow(w, e.Top+y, e.row)
+}
+
+func (e *PaddingEncoder) End(w io.Writer) error {
+	for y := 0; y < e.Bottom; y++ {
+		if err := e.Encoder.WriteRow(w, e.Top+e.rows+y, e.blank()); err != nil {
+			return err
+		}
+	}
+	return e.Encoder.End(w)
+}
+
+// blank clears the row buffer to spaces and returns it.
+func (e *PaddingEncoder) blank() []Cell {
+	for x := range e.row {
+		e.row[x] = Cell{Char: ' '}
+	}
+	return e.row
+}
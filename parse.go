@@ -0,0 +1,50 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// ParseASCII reconstructs a grayscale image from plain-text art rendered
+// with chars, one pixel per character. Each character maps back to the
+// brightness at its palette position, so the image has len(chars) levels.
+// Short rows are padded with black and a nil chars selects the default
+// palette.
+func ParseASCII(text string, chars []rune) (image.Image, error) {
+	if len(chars) == 0 {
+		chars = asciiChars
+	}
+	levels := make(map[rune]uint8, len(chars))
+	steps := float64(max(len(chars)-1, 1))
+	for i, r := range chars {
+		if _, ok := levels[r]; !ok {
+			levels[r] = uint8(math.Round(255 * float64(i) / steps))
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+	rows := make([][]rune, len(lines))
+	width := 0
+	for y, line := range lines {
+		rows[y] = []rune(line)
+		width = max(width, len(rows[y]))
+	}
+	if width == 0 {
+		return nil, fmt.Errorf("%w: no characters to parse", ErrInvalidDimensions)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, len(rows)))
+	for y, row := range rows {
+		for x, r := range row {
+			level, ok := levels[r]
+			if !ok {
+				return nil, fmt.Errorf("character %q at %d,%d is not in the palette", r, x, y)
+			}
+			img.SetGray(x, y, color.Gray{Y: level})
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,30 @@
+package ascii
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseASCIIRoundTrip(t *testing.T) {
+	const width = 64
+	src := grayRamp(width, 0, 255)
+	out, err := NewConverter(Options{Width: width, Height: 1}).Render(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseASCII(out, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Bounds(); got != src.Bounds() {
+		t.Fatalf("parsed bounds = %v, want %v", got, src.Bounds())
+	}
+	tolerance := 1 / float64(len(asciiChars))
+	for x := 0; x < width; x++ {
+		want := pixelBrightness(src.At(x, 0), LumaWeights709, 0)
+		got := pixelBrightness(parsed.At(x, 0), LumaWeights709, 0)
+		if math.Abs(got-want) >= tolerance {
+			t.Errorf("pixel %d: brightness %.3f after the round trip, want %.3f ± %.3f", x, got, want, tolerance)
+		}
+	}
+}
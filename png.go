@@ -0,0 +1,173 @@
+package ascii
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Glyph cells drawn by PNGEncoder are pngCellWidth×pngCellHeight pixels.
+const (
+	pngCellWidth  = 8
+	pngCellHeight = 16
+)
+
+// font8x16.bin holds the printable ASCII glyphs of the 7×13 fixed font from
+// golang.org/x/image/font/basicfont, padded to 8×16 cells. Each record is a
+// big-endian rune followed by 16 rows of 8 pixels, most significant bit on
+// the left.
+//
+//go:embed font8x16.bin
+var fontData []byte
+
+var glyphs = loadGlyphs(fontData)
+
+func loadGlyphs(data []byte) map[rune][pngCellHeight]byte {
+	m := map[rune][pngCellHeight]byte{}
+	for len(data) >= 4+pngCellHeight {
+		var rows [pngCellHeight]byte
+		copy(rows[:], data[4:])
+		m[rune(binary.BigEndian.Uint32(data))] = rows
+		data = data[4+pngCellHeight:]
+	}
+	return m
+}
+
+// PNGEncoder draws the character grid into an image with a built-in bitmap
+// font and writes it as a PNG. When Color is set each glyph is drawn in its
+// source pixel color, otherwise in white on black.
+type PNGEncoder struct {
+	Color bool
+
+	img *image.RGBA
+}
+
+func (e *PNGEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.img = image.NewRGBA(image.Rect(0, 0, cols*pngCellWidth, rows*pngCellHeight))
+	return nil
+}
+
+func (e *PNGEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	drawGlyphRow(e.img, y, row, e.Color)
+	return nil
+}
+
+func (e *PNGEncoder) End(w io.Writer) error {
+	return png.Encode(w, e.img)
+}
+
+// drawGlyphRow draws row y of the character grid into img. Glyphs are white
+// on black unless colored is set or a cell carries its own background.
+func drawGlyphRow(img *image.RGBA, y int, row []Cell, colored bool) {
+	for x, cell := range row {
+		fg, bg := color.RGBA{0xff, 0xff, 0xff, 0xff}, color.RGBA{0, 0, 0, 0xff}
+		if colored || cell.HasBackground {
+			fg = cell.Color
+			fg.A = 0xff
+		}
+		if cell.HasBackground {
+			bg = cell.Background
+			bg.A = 0xff
+		}
+		rows := glyphRows(cell.Char)
+		x0, y0 := x*pngCellWidth, y*pngCellHeight
+		for gy, bits := range rows {
+			for gx := 0; gx < pngCellWidth; gx++ {
+				c := bg
+				if bits&(0x80>>gx) != 0 {
+					c = fg
+				}
+				img.SetRGBA(x0+gx, y0+gy, c)
+			}
+		}
+	}
+}
+
+// glyphRows returns the bitmap of r. Block elements, shades, box-drawing
+// rules and braille patterns are drawn geometrically; other runes missing
+// from the font render as blanks.
+func glyphRows(r rune) [pngCellHeight]byte {
+	if rows, ok := glyphs[r]; ok {
+		return rows
+	}
+	var rows [pngCellHeight]byte
+	fill := func(x0, y0, x1, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				rows[y] |= 0x80 >> x
+			}
+		}
+	}
+	const w, h = pngCellWidth, pngCellHeight
+	switch {
+	case r == '·':
+		fill(3, 7, 5, 9)
+	case r == '█':
+		fill(0, 0, w, h)
+	case r == '▀':
+		fill(0, 0, w, h/2)
+	case r == '▄':
+		fill(0, h/2, w, h)
+	case r == '▌':
+		fill(0, 0, w/2, h)
+	case r == '▐':
+		fill(w/2, 0, w, h)
+	case r == '░' || r == '▒' || r == '▓':
+		// Ordered stipples covering a quarter, half and three quarters.
+		level := int(r-'░') + 1
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if int(bayer4[y%4][x%4]) < level*4 {
+					rows[y] |= 0x80 >> x
+				}
+			}
+		}
+	case r >= 0x2596 && r <= 0x259F:
+		for i, mask := range quarterBlocks {
+			if mask != r {
+				continue
+			}
+			if i&1 != 0 {
+				fill(0, 0, w/2, h/2)
+			}
+			if i&2 != 0 {
+				fill(w/2, 0, w, h/2)
+			}
+			if i&4 != 0 {
+				fill(0, h/2, w/2, h)
+			}
+			if i&8 != 0 {
+				fill(w/2, h/2, w, h)
+			}
+		}
+	case r >= 0x2800 && r <= 0x28FF:
+		for dy, dots := range brailleDots {
+			for dx, bit := range dots {
+				if (r-0x2800)&bit != 0 {
+					x, y := 1+dx*4, 1+dy*4
+					fill(x, y, x+2, y+2)
+				}
+			}
+		}
+	case r == '─' || r == '═':
+		fill(0, h/2-1, w, h/2+1)
+	case r == '│' || r == '║':
+		fill(w/2-1, 0, w/2+1, h)
+	case r == '┌' || r == '╔':
+		fill(w/2-1, h/2-1, w, h/2+1)
+		fill(w/2-1, h/2-1, w/2+1, h)
+	case r == '┐' || r == '╗':
+		fill(0, h/2-1, w/2+1, h/2+1)
+		fill(w/2-1, h/2-1, w/2+1, h)
+	case r == '└' || r == '╚':
+		fill(w/2-1, h/2-1, w, h/2+1)
+		fill(w/2-1, 0, w/2+1, h/2+1)
+	case r == '┘' || r == '╝':
+		fill(0, h/2-1, w/2+1, h/2+1)
+		fill(w/2-1, 0, w/2+1, h/2+1)
+	}
+	return rows
+}
@@ -0,0 +1,26 @@
+package ascii
+
+import "fmt"
+
+// ProfileOptions returns the Options of a named size and quality preset:
+//
+//	tiny    40×20, nearest-neighbor
+//	small   80×40, nearest-neighbor
+//	medium  120×60, bilinear
+//	large   160×80, bilinear, Floyd–Steinberg dithering
+//	hd      240×120, box filter, Floyd–Steinberg dithering, equalized
+func ProfileOptions(name string) (Options, error) {
+	switch name {
+	case "tiny":
+		return Options{Width: 40, Height: 20, Interpolation: InterpNearest}, nil
+	case "small":
+		return Options{Width: 80, Height: 40, Interpolation: InterpNearest}, nil
+	case "medium":
+		return Options{Width: 120, Height: 60, Interpolation: InterpBilinear}, nil
+	case "large":
+		return Options{Width: 160, Height: 80, Interpolation: InterpBilinear, Dither: DitherFloydSteinberg}, nil
+	case "hd":
+		return Options{Width: 240, Height: 120, Interpolation: InterpBox, Dither: DitherFloydSteinberg, Equalize: true}, nil
+	}
+	return Options{}, fmt.Errorf("unknown profile %q", name)
+}
@@ -0,0 +1,88 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/AbilityJLR/ascii/resize"
+)
+
+// ResizeImage scales img to newWidth×newHeight using nearest-neighbor sampling.
+//
+// Deprecated: use resize.Resize with resize.Nearest.
+func ResizeImage(img image.Image, newWidth, newHeight int) image.Image {
+	return resize.Resize(img, newWidth, newHeight, resize.Nearest)
+}
+
+// ResizeImageBilinear scales img to newWidth×newHeight, blending the four
+// source pixels surrounding each destination pixel.
+//
+// Deprecated: use resize.Resize with resize.Bilinear.
+func ResizeImageBilinear(img image.Image, newWidth, newHeight int) image.Image {
+	return resize.Resize(img, newWidth, newHeight, resize.Bilinear)
+}
+
+// ResizeImageBox scales img to newWidth×newHeight by averaging every source
+// pixel that falls inside each destination cell.
+//
+// Deprecated: use resize.Resize with resize.Box.
+func ResizeImageBox(img image.Image, newWidth, newHeight int) image.Image {
+	return resize.Resize(img, newWidth, newHeight, resize.Box)
+}
+
+// Interpolation selects the resampling algorithm used by Resize.
+type Interpolation int
+
+const (
+	// InterpAuto uses bilinear sampling when shrinking by more than 2× and
+	// nearest-neighbor otherwise.
+	InterpAuto Interpolation = iota
+	InterpNearest
+	InterpBilinear
+	InterpBox
+)
+
+// ParseInterpolation converts a name such as "bilinear" to an Interpolation.
+func ParseInterpolation(name string) (Interpolation, error) {
+	switch name {
+	case "auto":
+		return InterpAuto, nil
+	case "nearest":
+		return InterpNearest, nil
+	case "bilinear":
+		return InterpBilinear, nil
+	case "box":
+		return InterpBox, nil
+	}
+	return 0, fmt.Errorf("unknown interpolation %q", name)
+}
+
+// String returns the name ParseInterpolation accepts for i.
+func (i Interpolation) String() string {
+	switch i {
+	case InterpNearest:
+		return "nearest"
+	case InterpBilinear:
+		return "bilinear"
+	case InterpBox:
+		return "box"
+	}
+	return "auto"
+}
+
+// Resize scales img to newWidth×newHeight with the given algorithm.
+func Resize(img image.Image, newWidth, newHeight int, interp Interpolation) image.Image {
+	if interp == InterpAuto {
+		interp = InterpNearest
+		if img.Bounds().Dx() > 2*newWidth || img.Bounds().Dy() > 2*newHeight {
+			interp = InterpBilinear
+		}
+	}
+	switch interp {
+	case InterpBilinear:
+		return resize.Resize(img, newWidth, newHeight, resize.Bilinear)
+	case InterpBox:
+		return resize.Resize(img, newWidth, newHeight, resize.Box)
+	}
+	return resize.Resize(img, newWidth, newHeight, resize.Nearest)
+}
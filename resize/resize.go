@@ -0,0 +1,144 @@
+// Package resize scales images with nearest-neighbor, bilinear or box
+// sampling.
+package resize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Algorithm selects the resampling method used by Resize.
+type Algorithm int
+
+const (
+	// Nearest copies the source pixel nearest each destination pixel.
+	Nearest Algorithm = iota
+	// Bilinear blends the four source pixels around each destination pixel.
+	Bilinear
+	// Box averages every source pixel covered by each destination pixel,
+	// which suits large reductions.
+	Box
+)
+
+// Resize scales img to w×h. The result's bounds start at the origin.
+func Resize(img image.Image, w, h int, algorithm Algorithm) image.Image {
+	switch algorithm {
+	case Bilinear:
+		return bilinear(img, w, h)
+	case Box:
+		return box(img, w, h)
+	}
+	return nearest(img, w, h)
+}
+
+// nearest scales img using nearest-neighbor sampling.
+func nearest(img image.Image, newWidth, newHeight int) image.Image {
+	bounds := img.Bounds()
+	oldWidth := bounds.Dx()
+	oldHeight := bounds.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	xScale := float64(oldWidth) / float64(newWidth)
+	yScale := float64(oldHeight) / float64(newHeight)
+
+	for y := 0; y < newHeight; y++ {
+		for x := 0; x < newWidth; x++ {
+			// Sample the source pixel under the center of the destination
+			// pixel rather than under its top-left corner.
+			srcX := int(math.Floor((float64(x) + 0.5) * xScale))
+			srcY := int(math.Floor((float64(y) + 0.5) * yScale))
+			if srcX >= oldWidth {
+				srcX = oldWidth - 1
+			}
+			if srcY >= oldHeight {
+				srcY = oldHeight - 1
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+		}
+	}
+
+	return dst
+}
+
+// bilinear scales img by blending the four source pixels surrounding each
+// destination pixel.
+func bilinear(img image.Image, newWidth, newHeight int) image.Image {
+	bounds := img.Bounds()
+	oldWidth, oldHeight := bounds.Dx(), bounds.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	xScale := float64(oldWidth) / float64(newWidth)
+	yScale := float64(oldHeight) / float64(newHeight)
+
+	for y := 0; y < newHeight; y++ {
+		srcY := math.Max((float64(y)+0.5)*yScale-0.5, 0)
+		y0 := min(int(srcY), oldHeight-1)
+		y1 := min(y0+1, oldHeight-1)
+		fy := srcY - float64(y0)
+		for x := 0; x < newWidth; x++ {
+			srcX := math.Max((float64(x)+0.5)*xScale-0.5, 0)
+			x0 := min(int(srcX), oldWidth-1)
+			x1 := min(x0+1, oldWidth-1)
+			fx := srcX - float64(x0)
+
+			r00, g00, b00, a00 := img.At(bounds.Min.X+x0, bounds.Min.Y+y0).RGBA()
+			r10, g10, b10, a10 := img.At(bounds.Min.X+x1, bounds.Min.Y+y0).RGBA()
+			r01, g01, b01, a01 := img.At(bounds.Min.X+x0, bounds.Min.Y+y1).RGBA()
+			r11, g11, b11, a11 := img.At(bounds.Min.X+x1, bounds.Min.Y+y1).RGBA()
+			blend := func(v00, v10, v01, v11 uint32) uint16 {
+				top := float64(v00)*(1-fx) + float64(v10)*fx
+				bottom := float64(v01)*(1-fx) + float64(v11)*fx
+				return uint16(top*(1-fy) + bottom*fy + 0.5)
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: blend(r00, r10, r01, r11),
+				G: blend(g00, g10, g01, g11),
+				B: blend(b00, b10, b01, b11),
+				A: blend(a00, a10, a01, a11),
+			})
+		}
+	}
+
+	return dst
+}
+
+// box scales img by averaging every source pixel that falls inside each
+// destination cell.
+func box(img image.Image, newWidth, newHeight int) image.Image {
+	bounds := img.Bounds()
+	oldWidth, oldHeight := bounds.Dx(), bounds.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	xScale := float64(oldWidth) / float64(newWidth)
+	yScale := float64(oldHeight) / float64(newHeight)
+
+	for y := 0; y < newHeight; y++ {
+		y0 := min(int(float64(y)*yScale), oldHeight-1)
+		y1 := min(max(int(math.Ceil(float64(y+1)*yScale)), y0+1), oldHeight)
+		for x := 0; x < newWidth; x++ {
+			x0 := min(int(float64(x)*xScale), oldWidth-1)
+			x1 := min(max(int(math.Ceil(float64(x+1)*xScale)), x0+1), oldWidth)
+
+			var r, g, b, a uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+				}
+			}
+			n := uint64((x1 - x0) * (y1 - y0))
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
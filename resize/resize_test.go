@@ -0,0 +1,182 @@
+package resize
+
+import (
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+// checkerboard returns a w×h image of alternating black and white pixels.
+func checkerboard(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x+y)%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+func TestBoxAveragesCell(t *testing.T) {
+	got := Resize(checkerboard(10, 10), 1, 1, Box)
+	if c, want := got.At(0, 0), (color.RGBA{0x7f, 0x7f, 0x7f, 0xff}); c != want {
+		t.Errorf("box average of a checkerboard = %v, want %v", c, want)
+	}
+}
+
+var algorithms = []struct {
+	name      string
+	algorithm Algorithm
+}{
+	{"nearest", Nearest},
+	{"bilinear", Bilinear},
+	{"box", Box},
+}
+
+// blocks returns a 2n×2n image made of four n×n squares colored a, b, c and
+// d in reading order.
+func blocks(n int, a, b, c, d color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2*n, 2*n))
+	for y := 0; y < 2*n; y++ {
+		for x := 0; x < 2*n; x++ {
+			img.SetRGBA(x, y, [4]color.RGBA{a, b, c, d}[y/n*2+x/n])
+		}
+	}
+	return img
+}
+
+// checkPixels compares img with want, given in reading order.
+func checkPixels(t *testing.T, img image.Image, want [][]color.RGBA) {
+	t.Helper()
+	if b := img.Bounds(); b != image.Rect(0, 0, len(want[0]), len(want)) {
+		t.Fatalf("bounds = %v, want %dx%d at the origin", b, len(want[0]), len(want))
+	}
+	for y, row := range want {
+		for x, c := range row {
+			if got := color.RGBAModel.Convert(img.At(x, y)); got != c {
+				t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, c)
+			}
+		}
+	}
+}
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+	white = color.RGBA{255, 255, 255, 255}
+)
+
+func TestResizeIdentity(t *testing.T) {
+	src := blocks(1, red, green, blue, white)
+	for _, a := range algorithms {
+		t.Run(a.name, func(t *testing.T) {
+			checkPixels(t, Resize(src, 2, 2, a.algorithm), [][]color.RGBA{{red, green}, {blue, white}})
+		})
+	}
+}
+
+func TestResizeUpscale(t *testing.T) {
+	src := blocks(1, red, green, blue, white)
+	for _, a := range []Algorithm{Nearest, Box} {
+		checkPixels(t, Resize(src, 4, 4, a), [][]color.RGBA{
+			{red, red, green, green},
+			{red, red, green, green},
+			{blue, blue, white, white},
+			{blue, blue, white, white},
+		})
+	}
+	// Bilinear blends between pixels but keeps the corners.
+	up := Resize(src, 4, 4, Bilinear)
+	for _, corner := range []struct {
+		x, y int
+		want color.RGBA
+	}{{0, 0, red}, {3, 0, green}, {0, 3, blue}, {3, 3, white}} {
+		if got := color.RGBAModel.Convert(up.At(corner.x, corner.y)); got != corner.want {
+			t.Errorf("bilinear corner (%d, %d) = %v, want %v", corner.x, corner.y, got, corner.want)
+		}
+	}
+}
+
+func TestResizeNearestDuplicates(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.SetRGBA(0, 0, red)
+	src.SetRGBA(1, 0, blue)
+	checkPixels(t, Resize(src, 4, 1, Nearest), [][]color.RGBA{{red, red, blue, blue}})
+}
+
+func TestResizeDownscale(t *testing.T) {
+	src := blocks(4, red, green, blue, white)
+	for _, a := range algorithms {
+		t.Run(a.name, func(t *testing.T) {
+			checkPixels(t, Resize(src, 2, 2, a.algorithm), [][]color.RGBA{{red, green}, {blue, white}})
+		})
+	}
+}
+
+func TestResizeSinglePixel(t *testing.T) {
+	src := image.NewRGBA(image.Rect(5, 5, 6, 6))
+	src.SetRGBA(5, 5, blue)
+	for _, a := range algorithms {
+		t.Run(a.name, func(t *testing.T) {
+			checkPixels(t, Resize(src, 3, 2, a.algorithm), [][]color.RGBA{{blue, blue, blue}, {blue, blue, blue}})
+			checkPixels(t, Resize(blocks(2, red, red, red, red), 1, 1, a.algorithm), [][]color.RGBA{{red}})
+		})
+	}
+}
+
+func BenchmarkResize(b *testing.B) {
+	src := checkerboard(1000, 1000)
+	for _, a := range algorithms {
+		b.Run(a.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Resize(src, 100, 100, a.algorithm)
+			}
+		})
+	}
+}
+
+// photo returns a 4000×3000 image of random pixels, the size of a 12
+// megapixel camera photo, building it on first use.
+var photo = sync.OnceValue(func() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 4000, 3000))
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.Uint32())
+	}
+	return img
+})
+
+// benchmarkResizeNearest downscales photo by factor in each dimension and
+// reports the source megapixels processed per second.
+func benchmarkResizeNearest(b *testing.B, factor int) {
+	src := photo()
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Resize(src, w/factor, h/factor, Nearest)
+	}
+	b.ReportMetric(float64(w*h)*float64(b.N)/1e6/b.Elapsed().Seconds(), "Mpx/s")
+}
+
+func BenchmarkResizeNearest_10x(b *testing.B)   { benchmarkResizeNearest(b, 10) }
+func BenchmarkResizeNearest_100x(b *testing.B)  { benchmarkResizeNearest(b, 100) }
+func BenchmarkResizeNearest_1000x(b *testing.B) { benchmarkResizeNearest(b, 1000) }
+
+func BenchmarkResizeNearestParallel(b *testing.B) {
+	src := photo()
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Resize(src, w/10, h/10, Nearest)
+		}
+	})
+	b.ReportMetric(float64(w*h)*float64(b.N)/1e6/b.Elapsed().Seconds(), "Mpx/s")
+}
@@ -0,0 +1,85 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/AbilityJLR/ascii/exiforientation"
+)
+
+// Rotate90 rotates img 90° clockwise.
+//
+// Deprecated: use exiforientation.Rotate90.
+func Rotate90(img image.Image) image.Image {
+	return exiforientation.Rotate90(img)
+}
+
+// Rotate180 rotates img by 180°.
+//
+// Deprecated: use exiforientation.Rotate180.
+func Rotate180(img image.Image) image.Image {
+	return exiforientation.Rotate180(img)
+}
+
+// Rotate270 rotates img 90° counter-clockwise.
+//
+// Deprecated: use exiforientation.Rotate270.
+func Rotate270(img image.Image) image.Image {
+	return exiforientation.Rotate270(img)
+}
+
+// FlipHorizontal mirrors img left to right. The result is a view of img;
+// see exiforientation.View.
+func FlipHorizontal(img image.Image) image.Image {
+	return exiforientation.View(img, 2)
+}
+
+// FlipVertical mirrors img top to bottom. The result is a view of img; see
+// exiforientation.View.
+func FlipVertical(img image.Image) image.Image {
+	return exiforientation.View(img, 4)
+}
+
+// Orient applies the rotation described by an EXIF orientation value. The
+// result is a view of img rather than a copy; see exiforientation.View.
+func Orient(img image.Image, orientation int) image.Image {
+	return exiforientation.View(img, orientation)
+}
+
+// Rotate rotates img clockwise by 0, 90, 180 or 270 degrees. The result is a
+// view of img rather than a copy.
+func Rotate(img image.Image, degrees int) (image.Image, error) {
+	switch degrees {
+	case 0:
+		return img, nil
+	case 90:
+		return exiforientation.View(img, 6), nil
+	case 180:
+		return exiforientation.View(img, 3), nil
+	case 270:
+		return exiforientation.View(img, 8), nil
+	}
+	return nil, fmt.Errorf("unsupported rotation %d, want 0, 90, 180 or 270", degrees)
+}
+
+// Crop returns the part of img inside rect, given relative to the top-left
+// corner of img. The rectangle must be non-empty and lie within the image.
+func Crop(img image.Image, rect image.Rectangle) (image.Image, error) {
+	bounds := img.Bounds()
+	rect = rect.Add(bounds.Min)
+	if rect.Empty() {
+		return nil, fmt.Errorf("%w: crop rectangle %v is empty", ErrInvalidDimensions, rect.Sub(bounds.Min))
+	}
+	if !rect.In(bounds) {
+		return nil, fmt.Errorf("%w: crop rectangle %v lies outside the %dx%d image", ErrInvalidDimensions, rect.Sub(bounds.Min), bounds.Dx(), bounds.Dy())
+	}
+	if sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect), nil
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst, nil
+}
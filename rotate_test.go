@@ -0,0 +1,58 @@
+package ascii
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// numbered returns a w×h image whose pixel (x, y) has red x and green y, so
+// that every pixel is distinct.
+func numbered(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0, 255})
+		}
+	}
+	return img
+}
+
+// samePixels reports whether a and b have the same size and colors.
+func samePixels(a, b image.Image) bool {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Size() != bb.Size() {
+		return false
+	}
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			if color.RGBAModel.Convert(a.At(ab.Min.X+x, ab.Min.Y+y)) != color.RGBAModel.Convert(b.At(bb.Min.X+x, bb.Min.Y+y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestFlipTwiceIsIdentity(t *testing.T) {
+	src := numbered(4, 4)
+	for _, tc := range []struct {
+		name string
+		flip func(image.Image) image.Image
+		// corner is where the flip moves the top-left pixel.
+		corner image.Point
+	}{
+		{"horizontal", FlipHorizontal, image.Pt(3, 0)},
+		{"vertical", FlipVertical, image.Pt(0, 3)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			once := tc.flip(src)
+			if got, want := once.At(tc.corner.X, tc.corner.Y), src.At(0, 0); color.RGBAModel.Convert(got) != want {
+				t.Errorf("flipped pixel %v = %v, want %v", tc.corner, got, want)
+			}
+			if !samePixels(tc.flip(once), src) {
+				t.Error("flipping twice changed the image")
+			}
+		})
+	}
+}
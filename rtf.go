@@ -0,0 +1,101 @@
+package ascii
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// RTFEncoder writes an RTF document in a monospaced font. When Color is set
+// each run of identically colored characters is drawn with one \cfN
+// reference into a table of the colors used. The color table precedes the
+// text, so rows are buffered until End.
+type RTFEncoder struct {
+	Color bool
+
+	body    strings.Builder
+	colors  []color.RGBA
+	indices map[color.RGBA]int
+}
+
+func (e *RTFEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.body.Reset()
+	e.colors = nil
+	e.indices = map[color.RGBA]int{}
+	return nil
+}
+
+func (e *RTFEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	if y > 0 {
+		e.body.WriteString("\\line\n")
+	}
+	current := -1
+	for _, cell := range row {
+		if e.Color {
+			if index := e.colorIndex(cell.Color); index != current {
+				fmt.Fprintf(&e.body, "\\cf%d ", index)
+				current = index
+			}
+		}
+		writeRTFRune(&e.body, cell.Char)
+	}
+	return nil
+}
+
+func (e *RTFEncoder) End(w io.Writer) error {
+	if _, err := io.WriteString(w, "{\\rtf1\\ansi\\deff0{\\fonttbl{\\f0\\fmodern Courier New;}}\n"); err != nil {
+		return err
+	}
+	if e.Color {
+		var table strings.Builder
+		table.WriteString("{\\colortbl;")
+		for _, c := range e.colors {
+			fmt.Fprintf(&table, "\\red%d\\green%d\\blue%d;", c.R, c.G, c.B)
+		}
+		table.WriteString("}\n")
+		if _, err := io.WriteString(w, table.String()); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, "\\loch\\f0\\fs16\n"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, e.body.String()); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n}\n")
+	return err
+}
+
+// colorIndex returns the color table index of c, adding it on first use.
+// Index 0 is the default color, so table entries start at 1.
+func (e *RTFEncoder) colorIndex(c color.RGBA) int {
+	c.A = 0xff
+	if index, ok := e.indices[c]; ok {
+		return index
+	}
+	e.colors = append(e.colors, c)
+	e.indices[c] = len(e.colors)
+	return len(e.colors)
+}
+
+// writeRTFRune escapes r for an RTF text run.
+func writeRTFRune(sb *strings.Builder, r rune) {
+	switch {
+	case r == '\\' || r == '{' || r == '}':
+		sb.WriteByte('\\')
+		sb.WriteRune(r)
+	case r < 0x80:
+		sb.WriteRune(r)
+	case r > 0xFFFF:
+		// Runes outside the BMP are written as a UTF-16 surrogate pair.
+		hi, lo := utf16.EncodeRune(r)
+		writeRTFRune(sb, hi)
+		writeRTFRune(sb, lo)
+	default:
+		// \u takes a signed 16-bit value followed by an ASCII fallback.
+		fmt.Fprintf(sb, "\\u%d?", int16(r))
+	}
+}
@@ -0,0 +1,26 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteRTFRune(t *testing.T) {
+	for _, tc := range []struct {
+		r    rune
+		want string
+	}{
+		{'a', "a"},
+		{'{', `\{`},
+		{'\\', `\\`},
+		{'█', `\u9608?`},
+		{'\uFFFD', `\u-3?`},
+		{'\U0001FB00', `\u-10178?\u-8448?`}, // surrogates D83E DF00
+	} {
+		var sb strings.Builder
+		writeRTFRune(&sb, tc.r)
+		if got := sb.String(); got != tc.want {
+			t.Errorf("writeRTFRune(%U) = %q, want %q", tc.r, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ascii
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"image"
+	"io"
+)
+
+// RenderSideBySide renders left and right next to each other in half of
+// opts.Width each, divided by a │ column. When opts.Height is zero each
+// panel takes the height of its image, and the shorter one is padded with
+// blank rows.
+func RenderSideBySide(left, right image.Image, w io.Writer, opts Options) error {
+	panel := opts
+	panel.Width = (opts.Width - 1) / 2
+	if panel.Width <= 0 {
+		return fmt.Errorf("%w: width %d is too narrow for two panels", ErrInvalidDimensions, opts.Width)
+	}
+	c := NewConverter(panel)
+	leftRows, leftW, leftH, err := c.rowRenderer(context.Background(), left)
+	if err != nil {
+		return err
+	}
+	rightRows, rightW, rightH, err := c.rowRenderer(context.Background(), right)
+	if err != nil {
+		return err
+	}
+
+	width, height := leftW+1+rightW, max(leftH, rightH)
+	enc := c.encoder()
+	bw := bufio.NewWriter(w)
+	if err := enc.Begin(bw, width, height); err != nil {
+		return err
+	}
+	row := make([]Cell, width)
+	for y := 0; y < height; y++ {
+		fillPanel(leftRows, y, leftH, row[:leftW])
+		row[leftW] = borderCell('│')
+		fillPanel(rightRows, y, rightH, row[leftW+1:])
+		if err := enc.WriteRow(bw, y, row); err != nil {
+			return err
+		}
+	}
+	if err := enc.End(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// fillPanel renders row y of a panel height rows tall, or blanks past its
+// end.
+func fillPanel(rows rowRenderer, y, height int, dst []Cell) {
+	if y < height {
+		rows.row(y, dst)
+		return
+	}
+	for x := range dst {
+		dst[x] = Cell{Char: ' '}
+	}
+}
@@ -0,0 +1,116 @@
+package ascii
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+)
+
+// SIXELEncoder draws the character grid like PNGEncoder and writes it as a
+// DEC SIXEL image for terminals that support inline graphics. Colors are
+// reduced to a 6×6×6 palette.
+type SIXELEncoder struct {
+	Color bool
+
+	img *image.RGBA
+}
+
+func (e *SIXELEncoder) Begin(w io.Writer, cols, rows int) error {
+	e.img = image.NewRGBA(image.Rect(0, 0, cols*pngCellWidth, rows*pngCellHeight))
+	return nil
+}
+
+func (e *SIXELEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	drawGlyphRow(e.img, y, row, e.Color)
+	return nil
+}
+
+func (e *SIXELEncoder) End(w io.Writer) error {
+	return writeSIXEL(w, e.img)
+}
+
+// sixelLevel quantizes an 8-bit channel to one of six levels.
+func sixelLevel(v uint8) int {
+	return (int(v)*5 + 127) / 255
+}
+
+// writeSIXEL encodes img as a SIXEL stream using a 216-color palette. Each
+// band of six pixel rows is written once per color present in it, with runs
+// of identical columns compressed.
+func writeSIXEL(w io.Writer, img *image.RGBA) error {
+	bw := bufio.NewWriter(w)
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	fmt.Fprintf(bw, "\x1bPq\"1;1;%d;%d", width, height)
+
+	index := make([]int, width*height)
+	var used [216]bool
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := img.Pix[img.PixOffset(x, y):]
+			i := sixelLevel(p[0])*36 + sixelLevel(p[1])*6 + sixelLevel(p[2])
+			index[y*width+x] = i
+			used[i] = true
+		}
+	}
+	for i, ok := range used {
+		if ok {
+			fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+		}
+	}
+
+	bits := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		if top > 0 {
+			bw.WriteByte('-')
+		}
+		var present [216]bool
+		for y := top; y < min(top+6, height); y++ {
+			for x := 0; x < width; x++ {
+				present[index[y*width+x]] = true
+			}
+		}
+		first := true
+		for c, ok := range present {
+			if !ok {
+				continue
+			}
+			if !first {
+				bw.WriteByte('$')
+			}
+			first = false
+			for x := range bits {
+				bits[x] = 0
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if index[(top+dy)*width+x] == c {
+						bits[x] |= 1 << dy
+					}
+				}
+			}
+			fmt.Fprintf(bw, "#%d", c)
+			writeSIXELRuns(bw, bits)
+		}
+	}
+	bw.WriteString("\x1b\\")
+	return bw.Flush()
+}
+
+// writeSIXELRuns writes one color's sixels for a band, using the !n repeat
+// introducer for runs longer than three.
+func writeSIXELRuns(bw *bufio.Writer, bits []byte) {
+	for x := 0; x < len(bits); {
+		run := 1
+		for x+run < len(bits) && bits[x+run] == bits[x] {
+			run++
+		}
+		ch := '?' + bits[x]
+		if run > 3 {
+			fmt.Fprintf(bw, "!%d%c", run, ch)
+		} else {
+			for i := 0; i < run; i++ {
+				bw.WriteByte(ch)
+			}
+		}
+		x += run
+	}
+}
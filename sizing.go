@@ -0,0 +1,73 @@
+package ascii
+
+import "image"
+
+// FitDimensions returns the largest size no bigger than maxW×maxH that keeps
+// the srcW:srcH aspect ratio. Both results are at least 1.
+func FitDimensions(srcW, srcH, maxW, maxH int) (int, int) {
+	if srcW <= 0 || srcH <= 0 {
+		return max(maxW, 1), max(maxH, 1)
+	}
+	w, h := maxW, maxW*srcH/srcW
+	if h > maxH {
+		w, h = maxH*srcW/srcH, maxH
+	}
+	return max(w, 1), max(h, 1)
+}
+
+// FillDimensions returns the largest region of a srcW×srcH image that has the
+// dstW:dstH aspect ratio, i.e. the crop that fills dstW×dstH without
+// distortion.
+func FillDimensions(srcW, srcH, dstW, dstH int) (int, int) {
+	if dstW <= 0 || dstH <= 0 {
+		return srcW, srcH
+	}
+	w, h := srcW, srcW*dstH/dstW
+	if h > srcH {
+		w, h = srcH*dstW/dstH, srcH
+	}
+	return max(w, 1), max(h, 1)
+}
+
+// fillCrop center-crops img to the aspect ratio of a width×height grid of
+// cells whose width-to-height ratio is charAspect.
+func fillCrop(img image.Image, width, height int, charAspect float64) image.Image {
+	bounds := img.Bounds()
+	w, h := FillDimensions(bounds.Dx(), bounds.Dy(), width, int(float64(height)/charAspect))
+	x, y := (bounds.Dx()-w)/2, (bounds.Dy()-h)/2
+	cropped, err := Crop(img, image.Rect(x, y, x+w, y+h))
+	if err != nil {
+		return img
+	}
+	return cropped
+}
+
+// letterboxRows renders the image into a centered region of the grid and
+// fills the rest with blank cells.
+type letterboxRows struct {
+	inner  rowRenderer
+	x0, y0 int
+	w, h   int
+}
+
+func newLetterboxRows(img image.Image, width, height int, charAspect float64, opts Options) *letterboxRows {
+	bounds := img.Bounds()
+	w, h := FitDimensions(bounds.Dx(), int(float64(bounds.Dy())*charAspect), width, height)
+	return &letterboxRows{
+		inner: newRowRenderer(img, w, h, opts),
+		x0:    (width - w) / 2,
+		y0:    (height - h) / 2,
+		w:     w,
+		h:     h,
+	}
+}
+
+func (r *letterboxRows) row(y int, dst []Cell) {
+	for x := range dst {
+		dst[x] = Cell{Char: ' '}
+	}
+	if y < r.y0 || y >= r.y0+r.h {
+		return
+	}
+	r.inner.row(y-r.y0, dst[r.x0:r.x0+r.w])
+}
@@ -0,0 +1,60 @@
+package ascii
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestFitDimensions(t *testing.T) {
+	for _, tc := range []struct {
+		srcW, srcH, maxW, maxH int
+		wantW, wantH           int
+	}{
+		{200, 100, 80, 40, 80, 40},
+		{200, 100, 80, 80, 80, 40},
+		{100, 200, 80, 40, 20, 40},
+		{100, 100, 80, 40, 40, 40},
+		{1000, 1, 10, 10, 10, 1},
+		{0, 0, 80, 40, 80, 40},
+	} {
+		w, h := FitDimensions(tc.srcW, tc.srcH, tc.maxW, tc.maxH)
+		if w != tc.wantW || h != tc.wantH {
+			t.Errorf("FitDimensions(%d, %d, %d, %d) = %d, %d, want %d, %d", tc.srcW, tc.srcH, tc.maxW, tc.maxH, w, h, tc.wantW, tc.wantH)
+		}
+	}
+}
+
+func TestFillDimensions(t *testing.T) {
+	for _, tc := range []struct {
+		srcW, srcH, dstW, dstH int
+		wantW, wantH           int
+	}{
+		{200, 100, 80, 40, 200, 100},
+		{200, 100, 40, 40, 100, 100},
+		{100, 200, 80, 40, 100, 50},
+		{300, 100, 20, 10, 200, 100},
+		{100, 100, 0, 40, 100, 100},
+	} {
+		w, h := FillDimensions(tc.srcW, tc.srcH, tc.dstW, tc.dstH)
+		if w != tc.wantW || h != tc.wantH {
+			t.Errorf("FillDimensions(%d, %d, %d, %d) = %d, %d, want %d, %d", tc.srcW, tc.srcH, tc.dstW, tc.dstH, w, h, tc.wantW, tc.wantH)
+		}
+	}
+}
+
+func TestLetterboxPadsWithSpaces(t *testing.T) {
+	// A white square drawn with cells twice as tall as wide takes 2×1 cells
+	// in the middle of a 10×1 grid.
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	out, err := NewConverter(Options{Width: 10, Height: 1, CharAspect: 0.5, Letterbox: true}).Render(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimRight(out, "\n"), "    ██    "; got != want {
+		t.Errorf("letterboxed row = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package ascii
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// RenderStats summarizes a rendering for tuning options.
+type RenderStats struct {
+	// Cells is the number of characters in the grid.
+	Cells int
+	// Chars is the palette, and Counts how often each of its characters
+	// was used.
+	Chars  []rune
+	Counts map[rune]int
+	// Other counts characters outside the palette, such as -edges lines
+	// or block and Braille glyphs.
+	Other int
+	// Edges counts the cells drawn as edge lines. It is zero unless
+	// Options.Edges is set.
+	Edges int
+	// Mean and StdDev describe the source image brightness in [0, 1]; see
+	// BrightnessStats.
+	Mean, StdDev float64
+	// Stages holds the time taken by each render stage, in order.
+	Stages []StageTiming
+}
+
+// StageTiming is the duration of one stage reported to
+// Options.ProgressFunc.
+type StageTiming struct {
+	Stage   string
+	Elapsed time.Duration
+}
+
+// ComputeRenderStats counts how often each character of chars appears in
+// grid.
+func ComputeRenderStats(grid [][]rune, chars []rune) RenderStats {
+	stats := RenderStats{Chars: chars, Counts: make(map[rune]int, len(chars))}
+	for _, r := range chars {
+		stats.Counts[r] = 0
+	}
+	for _, row := range grid {
+		for _, r := range row {
+			stats.Cells++
+			if _, ok := stats.Counts[r]; ok {
+				stats.Counts[r]++
+			} else {
+				stats.Other++
+			}
+		}
+	}
+	return stats
+}
+
+// BrightnessStats returns the mean and standard deviation of the brightness
+// of every pixel in img. Zero weights select LumaWeights709.
+func BrightnessStats(img image.Image, luma LumaWeights) (mean, stddev float64) {
+	luma = Options{Luma: luma}.lumaWeights()
+	bounds := img.Bounds()
+	n := float64(bounds.Dx() * bounds.Dy())
+	if n == 0 {
+		return 0, 0
+	}
+	var sum, sumSquares float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			v := pixelBrightness(img.At(x, y), luma, 0)
+			sum += v
+			sumSquares += v * v
+		}
+	}
+	mean = sum / n
+	return mean, math.Sqrt(max(sumSquares/n-mean*mean, 0))
+}
+
+// PrintStats writes stats as a character histogram followed by the source
+// brightness and stage timings.
+func PrintStats(w io.Writer, stats RenderStats) error {
+	var sb strings.Builder
+	percent := func(n int) float64 {
+		if stats.Cells == 0 {
+			return 0
+		}
+		return 100 * float64(n) / float64(stats.Cells)
+	}
+	sb.WriteString("Characters:\n")
+	for _, r := range stats.Chars {
+		n := stats.Counts[r]
+		fmt.Fprintf(&sb, "  %-5q %5.1f%% %s\n", r, percent(n), strings.Repeat("#", int(math.Round(percent(n)/2))))
+	}
+	if stats.Other > 0 {
+		fmt.Fprintf(&sb, "  %-5s %5.1f%% (%d cells outside the palette)\n", "other", percent(stats.Other), stats.Other)
+	}
+	if stats.Edges > 0 {
+		fmt.Fprintf(&sb, "Edge cells: %d (%.1f%%)\n", stats.Edges, percent(stats.Edges))
+	}
+	fmt.Fprintf(&sb, "Source brightness: mean %.3f, stddev %.3f\n", stats.Mean, stats.StdDev)
+	if len(stats.Stages) > 0 {
+		sb.WriteString("Stages:\n")
+		for _, s := range stats.Stages {
+			fmt.Fprintf(&sb, "  %-10s %v\n", s.Stage, s.Elapsed)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,70 @@
+package ascii
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+const (
+	// svgCellWidth and svgCellHeight are the default cell size in SVG units.
+	svgCellWidth  = 6.0
+	svgCellHeight = 10.0
+	// svgGlyphAspect is the advance width of a monospace glyph as a
+	// fraction of its font size.
+	svgGlyphAspect = 0.6
+)
+
+// SVGEncoder writes an SVG document with one <text> element per character.
+// When Color is set each element is filled with its source pixel color.
+type SVGEncoder struct {
+	Color bool
+	// CellWidth and CellHeight are the size of a character cell in SVG
+	// units; 6×10 is used for zero values. The font size is the largest
+	// that fits a glyph in the cell.
+	CellWidth, CellHeight float64
+}
+
+// cell returns the cell size and the font size derived from it.
+func (e *SVGEncoder) cell() (width, height, fontSize float64) {
+	width, height = e.CellWidth, e.CellHeight
+	if width <= 0 {
+		width = svgCellWidth
+	}
+	if height <= 0 {
+		height = svgCellHeight
+	}
+	return width, height, min(height, width/svgGlyphAspect)
+}
+
+func (e *SVGEncoder) Begin(w io.Writer, cols, rows int) error {
+	cellW, cellH, fontSize := e.cell()
+	width, height := float64(cols)*cellW, float64(rows)*cellH
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %g %g\" width=\"%g\" height=\"%g\">\n"+
+		"<rect width=\"100%%\" height=\"100%%\" fill=\"#000\"/>\n"+
+		"<g font-family=\"monospace\" font-size=\"%g\" fill=\"#fff\">\n", width, height, width, height, fontSize)
+	return err
+}
+
+func (e *SVGEncoder) WriteRow(w io.Writer, y int, row []Cell) error {
+	cellW, cellH, fontSize := e.cell()
+	baseline := float64(y+1)*cellH - fontSize*0.2
+	for x, cell := range row {
+		if cell.Char == ' ' {
+			continue
+		}
+		fill := ""
+		if e.Color {
+			fill = fmt.Sprintf(" fill=\"rgb(%d,%d,%d)\"", cell.Color.R, cell.Color.G, cell.Color.B)
+		}
+		if _, err := fmt.Fprintf(w, "<text x=\"%g\" y=\"%g\"%s>%s</text>\n", float64(x)*cellW, baseline, fill, html.EscapeString(string(cell.Char))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *SVGEncoder) End(w io.Writer) error {
+	_, err := io.WriteString(w, "</g>\n</svg>\n")
+	return err
+}
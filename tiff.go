@@ -0,0 +1,74 @@
+package ascii
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+
+	"golang.org/x/image/tiff"
+)
+
+// tiffPageOffsets walks the IFD chain of a TIFF file and returns the offset
+// of every page's IFD.
+func tiffPageOffsets(data []byte) ([]uint32, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("%w: truncated TIFF header", ErrImageDecode)
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%w: invalid TIFF byte order", ErrImageDecode)
+	}
+
+	var offsets []uint32
+	seen := map[uint32]bool{}
+	for offset := order.Uint32(data[4:8]); offset != 0; {
+		if seen[offset] || int(offset)+2 > len(data) {
+			return nil, fmt.Errorf("%w: invalid IFD offset", ErrImageDecode)
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+		next := int(offset) + 2 + int(order.Uint16(data[offset:offset+2]))*12
+		if next+4 > len(data) {
+			break
+		}
+		offset = order.Uint32(data[next : next+4])
+	}
+	return offsets, nil
+}
+
+// TIFFPageCount returns the number of pages in a TIFF file.
+func TIFFPageCount(data []byte) (int, error) {
+	offsets, err := tiffPageOffsets(data)
+	return len(offsets), err
+}
+
+// DecodeTIFFPage decodes a single page of a multi-page TIFF file.
+func DecodeTIFFPage(data []byte, page int) (image.Image, error) {
+	offsets, err := tiffPageOffsets(data)
+	if err != nil {
+		return nil, err
+	}
+	if page < 0 || page >= len(offsets) {
+		return nil, fmt.Errorf("page %d out of range, file has %d", page, len(offsets))
+	}
+
+	// The decoder only reads the first IFD, so point the header at the
+	// requested page instead.
+	patched := append([]byte(nil), data...)
+	if string(data[:2]) == "II" {
+		binary.LittleEndian.PutUint32(patched[4:8], offsets[page])
+	} else {
+		binary.BigEndian.PutUint32(patched[4:8], offsets[page])
+	}
+	img, err := tiff.Decode(bytes.NewReader(patched))
+	if err != nil {
+		return nil, fmt.Errorf("%w: page %d: %w", ErrImageDecode, page, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,27 @@
+package ascii
+
+import (
+	"image"
+	"image/draw"
+)
+
+// TileImages lays images out left to right, top to bottom on a black canvas
+// of cols×rows tiles, each tileW×tileH pixels. Every image is scaled to fit
+// its tile without distortion and centered in it. Images beyond cols×rows
+// are ignored.
+func TileImages(images []image.Image, cols, rows, tileW, tileH int) image.Image {
+	canvas := image.NewRGBA(image.Rect(0, 0, cols*tileW, rows*tileH))
+	draw.Draw(canvas, canvas.Bounds(), image.Black, image.Point{}, draw.Src)
+	for i, img := range images {
+		if i >= cols*rows {
+			break
+		}
+		bounds := img.Bounds()
+		w, h := FitDimensions(bounds.Dx(), bounds.Dy(), tileW, tileH)
+		x := i%cols*tileW + (tileW-w)/2
+		y := i/cols*tileH + (tileH-h)/2
+		scaled := Resize(img, w, h, InterpAuto)
+		draw.Draw(canvas, image.Rect(x, y, x+w, y+h), scaled, image.Point{}, draw.Src)
+	}
+	return canvas
+}